@@ -0,0 +1,217 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultAdaptiveInitialLimit 自适应并发限制的初始并发数
+	defaultAdaptiveInitialLimit = 20
+	// defaultAdaptiveMinLimit 自适应并发限制的最小并发数
+	defaultAdaptiveMinLimit = 1
+	// defaultAdaptiveMaxLimit 自适应并发限制的最大并发数
+	defaultAdaptiveMaxLimit = 1000
+	// defaultAdaptiveSmoothing 每次调整限制时新值所占的权重
+	defaultAdaptiveSmoothing = 0.2
+	// defaultAdaptiveTolerance 允许短期耗时超过长期基线的倍数，超过后开始收缩限制
+	defaultAdaptiveTolerance = 1.5
+	// adaptiveLongRTTAlpha 长期耗时基线的指数移动平均系数
+	adaptiveLongRTTAlpha = 0.05
+)
+
+// AdaptiveOption 自适应并发限制拦截器配置项
+type AdaptiveOption func(*adaptiveOptions)
+
+// adaptiveOptions 自适应并发限制拦截器配置
+type adaptiveOptions struct {
+	initialLimit int
+	minLimit     int
+	maxLimit     int
+	smoothing    float64
+	tolerance    float64
+}
+
+// WithAdaptiveInitialLimit 设置初始并发限制，默认 20
+func WithAdaptiveInitialLimit(n int) AdaptiveOption {
+	return func(o *adaptiveOptions) {
+		if n > 0 {
+			o.initialLimit = n
+		}
+	}
+}
+
+// WithAdaptiveLimitRange 设置并发限制的调整范围，默认 [1, 1000]
+func WithAdaptiveLimitRange(minLimit, maxLimit int) AdaptiveOption {
+	return func(o *adaptiveOptions) {
+		if minLimit > 0 && maxLimit >= minLimit {
+			o.minLimit = minLimit
+			o.maxLimit = maxLimit
+		}
+	}
+}
+
+// WithAdaptiveSmoothing 设置每次调整限制时新值所占的权重，取值 (0, 1]，默认 0.2，越大调整越快
+func WithAdaptiveSmoothing(smoothing float64) AdaptiveOption {
+	return func(o *adaptiveOptions) {
+		if smoothing > 0 && smoothing <= 1 {
+			o.smoothing = smoothing
+		}
+	}
+}
+
+// WithAdaptiveTolerance 设置允许短期耗时超过长期基线的倍数，默认 1.5，超过后开始收缩限制
+func WithAdaptiveTolerance(tolerance float64) AdaptiveOption {
+	return func(o *adaptiveOptions) {
+		if tolerance >= 1 {
+			o.tolerance = tolerance
+		}
+	}
+}
+
+// AdaptiveConcurrencyUnaryInterceptor 创建自适应并发限制拦截器
+// 参考 Gradient2 算法：以请求耗时的长期移动平均作为基线，耗时升高时按比例收缩并发限制，
+// 耗时稳定且并发接近上限时逐步放大限制；超时（DeadlineExceeded）时按 AIMD 方式乘性减小。
+// 处理中的请求数达到当前限制时，新请求直接返回 ResourceExhausted。
+// 当前限制和处理中的请求数分别记录在 GRPCAdaptiveConcurrencyLimit、GRPCAdaptiveConcurrencyInFlight，
+// 同一进程内使用多个自适应拦截器时这两个指标会互相覆盖
+func AdaptiveConcurrencyUnaryInterceptor(opts ...AdaptiveOption) grpc.UnaryServerInterceptor {
+	o := &adaptiveOptions{
+		initialLimit: defaultAdaptiveInitialLimit,
+		minLimit:     defaultAdaptiveMinLimit,
+		maxLimit:     defaultAdaptiveMaxLimit,
+		smoothing:    defaultAdaptiveSmoothing,
+		tolerance:    defaultAdaptiveTolerance,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	l := newAdaptiveLimiter(o)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if !l.acquire() {
+			GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "adaptive_limit").Inc()
+			return nil, status.Error(codes.ResourceExhausted, "server overloaded, concurrency limit reached")
+		}
+
+		start := nowFunc()
+		// 处理器 panic 时同样需要归还并发名额
+		defer func() { l.release(since(start), status.Code(err)) }()
+
+		return handler(ctx, req)
+	}
+}
+
+// adaptiveLimiter 根据请求耗时调整并发限制
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	opts     *adaptiveOptions
+	limit    float64
+	inFlight int
+	// longRTT 请求耗时的长期移动平均（秒），为 0 表示还没有样本
+	longRTT float64
+}
+
+// newAdaptiveLimiter 创建自适应限制器
+func newAdaptiveLimiter(o *adaptiveOptions) *adaptiveLimiter {
+	l := &adaptiveLimiter{
+		opts:  o,
+		limit: clampLimit(float64(o.initialLimit), o),
+	}
+	GRPCAdaptiveConcurrencyLimit.Set(l.limit)
+	GRPCAdaptiveConcurrencyInFlight.Set(0)
+	return l
+}
+
+// acquire 处理中的请求数未达到限制时登记一个请求
+func (l *adaptiveLimiter) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight >= int(l.limit) {
+		return false
+	}
+	l.inFlight++
+	GRPCAdaptiveConcurrencyInFlight.Set(float64(l.inFlight))
+	return true
+}
+
+// release 请求结束，根据耗时和状态码调整限制
+func (l *adaptiveLimiter) release(rtt time.Duration, code codes.Code) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inFlight := l.inFlight
+	l.inFlight--
+	GRPCAdaptiveConcurrencyInFlight.Set(float64(l.inFlight))
+
+	if code == codes.DeadlineExceeded {
+		// 超时说明已经过载，乘性减小
+		l.limit = clampLimit(l.limit*0.9, l.opts)
+	} else {
+		l.update(rtt.Seconds(), inFlight)
+	}
+	GRPCAdaptiveConcurrencyLimit.Set(l.limit)
+}
+
+// update 按 Gradient2 算法根据单次耗时样本调整限制，inFlight 为该请求结束前的处理中请求数
+func (l *adaptiveLimiter) update(shortRTT float64, inFlight int) {
+	if shortRTT <= 0 {
+		return
+	}
+	if l.longRTT == 0 {
+		l.longRTT = shortRTT
+	}
+	l.longRTT = l.longRTT*(1-adaptiveLongRTTAlpha) + shortRTT*adaptiveLongRTTAlpha
+
+	// 耗时持续升高后基线向样本漂移，避免基线长期偏低导致限制无法恢复
+	if shortRTT/l.longRTT > 2 {
+		l.longRTT *= 0.95
+	}
+
+	gradient := math.Max(0.5, math.Min(1, l.opts.tolerance*l.longRTT/shortRTT))
+
+	// 并发远低于限制时说明请求量不足，不能据此放大限制
+	if gradient == 1 && float64(inFlight) < l.limit/2 {
+		return
+	}
+
+	queueSize := math.Sqrt(l.limit)
+	newLimit := l.limit*gradient + queueSize
+	newLimit = l.limit*(1-l.opts.smoothing) + newLimit*l.opts.smoothing
+	l.limit = clampLimit(newLimit, l.opts)
+}
+
+// currentLimit 返回当前的并发限制
+func (l *adaptiveLimiter) currentLimit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// clampLimit 将限制约束在配置的范围内
+func clampLimit(limit float64, o *adaptiveOptions) float64 {
+	return math.Max(float64(o.minLimit), math.Min(float64(o.maxLimit), limit))
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdaptiveConcurrencyUnaryInterceptor_ShedsLoad(t *testing.T) {
+	interceptor := AdaptiveConcurrencyUnaryInterceptor(
+		WithAdaptiveInitialLimit(1),
+		WithAdaptiveLimitRange(1, 1),
+	)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Busy",
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "response", nil
+		})
+	}()
+	<-started
+
+	if got := testutil.ToFloat64(GRPCAdaptiveConcurrencyInFlight); got != 1 {
+		t.Errorf("inflight gauge = %v, want 1", got)
+	}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("handler should not be called when the limit is reached")
+		return "response", nil
+	})
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.ResourceExhausted)
+	}
+
+	close(release)
+	<-done
+
+	if got := testutil.ToFloat64(GRPCAdaptiveConcurrencyInFlight); got != 0 {
+		t.Errorf("inflight gauge = %v, want 0", got)
+	}
+}
+
+func TestAdaptiveConcurrencyUnaryInterceptor_ReleasesOnPanic(t *testing.T) {
+	interceptor := AdaptiveConcurrencyUnaryInterceptor(
+		WithAdaptiveInitialLimit(1),
+		WithAdaptiveLimitRange(1, 1),
+	)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Panics",
+	}
+
+	assertPanics(t, "handler", func() {
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("boom")
+		})
+	})
+
+	var handlerCalled bool
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCalled = true
+		return "response", nil
+	})
+	if err != nil || !handlerCalled {
+		t.Errorf("interceptor() after panic: err = %v, handler called = %v, want slot released", err, handlerCalled)
+	}
+}
+
+func TestAdaptiveLimiter_AdjustsToLatency(t *testing.T) {
+	l := newAdaptiveLimiter(&adaptiveOptions{
+		initialLimit: 20,
+		minLimit:     1,
+		maxLimit:     100,
+		smoothing:    defaultAdaptiveSmoothing,
+		tolerance:    defaultAdaptiveTolerance,
+	})
+
+	// 耗时稳定且并发接近上限时放大限制
+	for i := 0; i < 20; i++ {
+		l.update(0.01, l.currentLimit())
+	}
+	grown := l.currentLimit()
+	if grown <= 20 {
+		t.Fatalf("limit after steady latency = %d, want > 20", grown)
+	}
+
+	// 并发远低于限制时不放大
+	l.update(0.01, 1)
+	if got := l.currentLimit(); got != grown {
+		t.Errorf("limit with low concurrency = %d, want %d", got, grown)
+	}
+
+	// 耗时大幅升高时收缩限制
+	for i := 0; i < 20; i++ {
+		l.update(0.2, l.currentLimit())
+	}
+	shrunk := l.currentLimit()
+	if shrunk >= grown {
+		t.Errorf("limit after latency spike = %d, want < %d", shrunk, grown)
+	}
+
+	// 超时时乘性减小
+	l.acquire()
+	l.release(time.Second, codes.DeadlineExceeded)
+	if got := l.currentLimit(); got >= shrunk {
+		t.Errorf("limit after deadline exceeded = %d, want < %d", got, shrunk)
+	}
+	if got := testutil.ToFloat64(GRPCAdaptiveConcurrencyLimit); int(got) != l.currentLimit() {
+		t.Errorf("limit gauge = %v, want %d", got, l.currentLimit())
+	}
+}
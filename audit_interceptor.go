@@ -0,0 +1,114 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// auditRecord 写入审计日志的单条 JSON 记录
+type auditRecord struct {
+	Timestamp string `json:"timestamp"`
+	Method    string `json:"method"`
+	Caller    string `json:"caller,omitempty"`
+	Peer      string `json:"peer,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Code      string `json:"code"`
+}
+
+// AuditUnaryInterceptor 创建审计日志拦截器
+// 对 methods 中值为 true 的方法（完整方法名），请求结束后向 w 写入一行 JSON 审计记录，
+// 字段为 timestamp、method、caller（已验证客户端证书的 Common Name）、peer、request_id 和 code。
+// 审计日志独立于应用日志和追踪，写入失败只记录警告，不影响请求结果；
+// 每条记录写入后会调用 w 的 Sync 或 Flush（如 *os.File、*bufio.Writer），避免进程崩溃时丢失
+func AuditUnaryInterceptor(methods map[string]bool, w io.Writer) grpc.UnaryServerInterceptor {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := fullMethodFromInfo(info)
+		if !methods[method] {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		record := auditRecord{
+			Timestamp: nowFunc().UTC().Format(time.RFC3339Nano),
+			Method:    method,
+			Caller:    auditCaller(ctx),
+			RequestID: auditRequestID(ctx),
+			Code:      status.Code(err).String(),
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			record.Peer = p.Addr.String()
+		}
+
+		mu.Lock()
+		writeErr := enc.Encode(record)
+		if writeErr == nil {
+			writeErr = flushWriter(w)
+		}
+		mu.Unlock()
+		if writeErr != nil {
+			log.Warn("Failed to write audit record", zap.String("method", method), zap.Error(writeErr))
+		}
+
+		return resp, err
+	}
+}
+
+// auditCaller 返回调用方身份，优先使用 mTLS 拦截器放入 context 的 Common Name
+func auditCaller(ctx context.Context) string {
+	if commonName := PeerCommonNameFromContext(ctx); commonName != "" {
+		return commonName
+	}
+	commonName, _ := verifiedPeerCommonName(ctx)
+	return commonName
+}
+
+// auditRequestID 返回请求 ID，审计拦截器位于 trace 拦截器之前时从 metadata 中读取
+func auditRequestID(ctx context.Context) string {
+	if requestID := log.RequestIDFromContext(ctx); requestID != "" {
+		return requestID
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return lookupMetadata(md, "x-request-id")
+}
+
+// flushWriter 将 w 中缓冲的数据落盘或写出，w 不支持时直接返回
+func flushWriter(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Sync() error }:
+		return f.Sync()
+	case interface{ Flush() error }:
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuditUnaryInterceptor(t *testing.T) {
+	setNowFunc(t, func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) })
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	interceptor := AuditUnaryInterceptor(map[string]bool{"/test.Admin/Delete": true}, w)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	ctx = contextWithPeerCommonName(ctx, "admin-client")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+
+	for _, method := range []string{"/test.Admin/Delete", "/test.Service/Get"} {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("interceptor(%s) code = %v, want %v", method, status.Code(err), codes.PermissionDenied)
+		}
+	}
+
+	// 每条记录写入后立即 Flush，bufio.Writer 中不应残留数据
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("audit lines = %d, want 1: %q", len(lines), buf.String())
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("unmarshal audit record: %v", err)
+	}
+	want := map[string]interface{}{
+		"timestamp":  "2025-01-02T03:04:05Z",
+		"method":     "/test.Admin/Delete",
+		"caller":     "admin-client",
+		"request_id": "req-1",
+		"code":       codes.PermissionDenied.String(),
+	}
+	for k, v := range want {
+		if record[k] != v {
+			t.Errorf("record[%q] = %v, want %v", k, record[k], v)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"github.com/go-anyway/framework-log"
+
+	"go.uber.org/zap"
+)
+
+// chainKey 存放当前请求已经执行的命名拦截器列表
+const chainKey = contextKey("interceptorChain")
+
+// InterceptorChainFromContext 返回当前请求已经执行过的命名拦截器（通过 WithChainName 命名），按执行顺序排列
+// 在处理器中调用即可得到实际生效的拦截器链顺序，用于排查拦截器顺序配置错误
+func InterceptorChainFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	chain, _ := ctx.Value(chainKey).([]string)
+	return append([]string(nil), chain...)
+}
+
+// enterChain 将命名拦截器追加到 context 中的拦截器链，未设置 chainName 时原样返回
+// 每个拦截器首次执行时以 debug 级别记录其在链中的位置
+func (o *options) enterChain(ctx context.Context) context.Context {
+	if o.chainName == "" {
+		return ctx
+	}
+
+	prev, _ := ctx.Value(chainKey).([]string)
+	chain := make([]string, len(prev), len(prev)+1)
+	copy(chain, prev)
+	chain = append(chain, o.chainName)
+
+	o.chainLogOnce.Do(func() {
+		log.Debug("Interceptor first invoked",
+			zap.String("interceptor", o.chainName),
+			zap.Int("position", len(chain)),
+			zap.Strings("chain", chain),
+		)
+	})
+
+	return context.WithValue(ctx, chainKey, chain)
+}
+
+// chainMemberOptions 返回默认拦截器链中名为 name 的拦截器使用的选项
+// 设置了 WithChainName 时以其为前缀派生 "<chainName>.<name>"，使链中每个拦截器的名称互不相同
+func chainMemberOptions(opts []Option, name string) []Option {
+	chainName := newOptions(opts...).chainName
+	if chainName == "" {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], WithChainName(chainName+"."+name))
+}
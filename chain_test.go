@@ -0,0 +1,98 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+func TestInterceptorChainFromContext(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	trace := TraceUnaryInterceptor(WithChainName("trace"))
+	metrics := MetricsUnaryInterceptorWithRegistry(prometheus.NewRegistry(), WithChainName("metrics"))
+	tenant := TenantUnaryInterceptor("x-tenant-id", WithChainName("tenant"))
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	var got []string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = InterceptorChainFromContext(ctx)
+		return "response", nil
+	}
+
+	// 按 trace -> metrics -> tenant 的顺序串联拦截器
+	call := func() {
+		_, err := trace(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return metrics(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return tenant(ctx, req, info, handler)
+			})
+		})
+		if err != nil {
+			t.Fatalf("interceptor chain returned unexpected error: %v", err)
+		}
+	}
+	call()
+	call()
+
+	want := []string{"trace", "metrics", "tenant"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InterceptorChainFromContext() = %v, want %v", got, want)
+	}
+
+	var logged int
+	for _, entry := range readLogs() {
+		if entry["msg"] == "Interceptor first invoked" {
+			logged++
+		}
+	}
+	if logged != len(want) {
+		t.Errorf("position logs = %d, want %d (once per interceptor)", logged, len(want))
+	}
+}
+
+func TestDefaultUnaryServerInterceptors_ChainNames(t *testing.T) {
+	interceptors := DefaultUnaryServerInterceptors(WithChainName("server"))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	var got []string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = InterceptorChainFromContext(ctx)
+		return "response", nil
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	if _, err := handler(context.Background(), nil); err != nil {
+		t.Fatalf("interceptor chain returned unexpected error: %v", err)
+	}
+
+	want := []string{"server.trace", "server.metrics"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InterceptorChainFromContext() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultUnaryClientInterceptors 返回推荐顺序的客户端一元拦截器链：trace（最外层）、retry、metrics
+// metrics 在最内层，每次重试尝试都会单独计数
+// 设置 WithChainName 时各拦截器分别命名为 <name>.trace、<name>.retry、<name>.metrics
+func DefaultUnaryClientInterceptors(opts ...Option) []grpc.UnaryClientInterceptor {
+	return []grpc.UnaryClientInterceptor{
+		TraceUnaryClientInterceptor(chainMemberOptions(opts, "trace")...),
+		RetryUnaryClientInterceptor(chainMemberOptions(opts, "retry")...),
+		MetricsUnaryClientInterceptor(chainMemberOptions(opts, "metrics")...),
+	}
+}
+
+// DefaultStreamClientInterceptors 返回推荐顺序的客户端流拦截器链：trace（最外层）、metrics
+// 流式调用不支持自动重试
+func DefaultStreamClientInterceptors(opts ...Option) []grpc.StreamClientInterceptor {
+	return []grpc.StreamClientInterceptor{
+		TraceStreamClientInterceptor(chainMemberOptions(opts, "trace")...),
+		MetricsStreamClientInterceptor(chainMemberOptions(opts, "metrics")...),
+	}
+}
+
+// DialOptions 返回安装默认客户端拦截器链的 DialOption，可直接传给 grpc.NewClient
+func DialOptions(opts ...Option) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(DefaultUnaryClientInterceptors(opts...)...),
+		grpc.WithChainStreamInterceptor(DefaultStreamClientInterceptors(opts...)...),
+	}
+}
+
+// MetricsUnaryClientInterceptor 创建 gRPC 客户端 metrics 拦截器，调用结果记录到 GRPCClientRequestTotal 和 GRPCClientRequestDuration
+func MetricsUnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = o.enterChain(ctx)
+		start := nowFunc()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		recordClientCall(o.methodLabel(method), err, since(start))
+		return err
+	}
+}
+
+// MetricsStreamClientInterceptor 创建 gRPC 客户端流 metrics 拦截器，流结束时记录结果和整个流的耗时
+func MetricsStreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = o.enterChain(ctx)
+		start := nowFunc()
+		label := o.methodLabel(method)
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			recordClientCall(label, err, since(start))
+			return nil, err
+		}
+
+		return newFinishingClientStream(cs, desc, func(err error) {
+			recordClientCall(label, err, since(start))
+		}), nil
+	}
+}
+
+// recordClientCall 记录单次客户端调用的结果和耗时
+func recordClientCall(method string, err error, elapsed time.Duration) {
+	code := statusCodeString(err)
+	GRPCClientRequestTotal.WithLabelValues(method, code).Inc()
+	GRPCClientRequestDuration.WithLabelValues(method, code).Observe(elapsed.Seconds())
+}
+
+// PerMethodDeadlineClientInterceptor 创建按方法设置 deadline 的客户端拦截器，集中管理下游调用的超时策略
+// 调用方的 context 已有 deadline 时保持不变；否则使用 deadlines 中该完整方法名对应的时限，
+// 不在 deadlines 中的方法使用 fallback。时限 <= 0 时不设置 deadline
+func PerMethodDeadlineClientInterceptor(deadlines map[string]time.Duration, fallback time.Duration) grpc.UnaryClientInterceptor {
+	timeouts := make(map[string]time.Duration, len(deadlines))
+	for method, d := range deadlines {
+		timeouts[method] = d
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		timeout, ok := timeouts[method]
+		if !ok {
+			timeout = fallback
+		}
+		if timeout <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// TraceStreamClientInterceptor 创建 gRPC 客户端流拦截器，支持 OpenTelemetry
+// 为整个流创建一个子 span（附加 LinkSpans 放入 context 的链接）并将追踪上下文注入到 metadata，流结束时结束 span
+func TraceStreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = o.enterChain(ctx)
+		if o.propagationWarnings {
+			warnMissingParentSpan(ctx, method)
+		}
+
+		ctx, span := o.startSpan(ctx, o.spanName(method), oteltrace.WithLinks(spanLinksFromContext(ctx)...))
+		recording := span.IsRecording()
+		if recording {
+			span.SetAttributes(o.spanAttributes...)
+			span.SetAttributes(
+				attribute.String("rpc.method", method),
+				attribute.String("rpc.system", "grpc"),
+			)
+		}
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if !ok {
+			md = metadata.MD{}
+		}
+		injectTraceContext(ctx, md)
+		setCausationHeader(ctx, md)
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		finish := func(err error) {
+			if recording {
+				span.SetAttributes(attribute.String("rpc.status_code", statusCodeString(err)))
+				o.setNumericStatus(span, err)
+				if err != nil {
+					span.RecordError(err)
+				}
+			}
+			span.End()
+		}
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			finish(err)
+			return nil, err
+		}
+		return newFinishingClientStream(cs, desc, finish), nil
+	}
+}
+
+// finishingClientStream 在流结束时回调 finish 的 ClientStream
+// 流结束指 RecvMsg 返回错误（io.EOF 视为正常结束），或非服务端流的调用收到唯一的响应
+type finishingClientStream struct {
+	grpc.ClientStream
+	desc   *grpc.StreamDesc
+	finish func(err error)
+	once   sync.Once
+}
+
+// newFinishingClientStream 包装 ClientStream，流结束时回调 finish
+func newFinishingClientStream(cs grpc.ClientStream, desc *grpc.StreamDesc, finish func(err error)) *finishingClientStream {
+	return &finishingClientStream{
+		ClientStream: cs,
+		desc:         desc,
+		finish:       finish,
+	}
+}
+
+func (s *finishingClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.done(nil)
+	case err != nil:
+		s.done(err)
+	case s.desc != nil && !s.desc.ServerStreams:
+		s.done(nil)
+	}
+	return err
+}
+
+func (s *finishingClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.done(err)
+	}
+	return err
+}
+
+func (s *finishingClientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.done(err)
+	}
+	return md, err
+}
+
+// done 只回调一次 finish，err 会被转换为 gRPC status
+func (s *finishingClientStream) done(err error) {
+	s.once.Do(func() {
+		if err != nil {
+			err = status.Convert(err).Err()
+		}
+		s.finish(err)
+	})
+}
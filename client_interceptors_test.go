@@ -0,0 +1,203 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newHealthClient 启动一个基于 bufconn 的健康检查服务，返回使用 dialOpts 连接的客户端
+func newHealthClient(t *testing.T, dialOpts ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", dialOpts...)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestDialOptions(t *testing.T) {
+	recorder := setupTestTracer(t)
+	client := newHealthClient(t, DialOptions()...)
+
+	const checkMethod = "/grpc.health.v1.Health/Check"
+	const watchMethod = "/grpc.health.v1.Health/Watch"
+	checkCounter := GRPCClientRequestTotal.WithLabelValues(checkMethod, codes.OK.String())
+	watchCounter := GRPCClientRequestTotal.WithLabelValues(watchMethod, codes.Canceled.String())
+	checkBefore := testutil.ToFloat64(checkCounter)
+	watchBefore := testutil.ToFloat64(watchCounter)
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch() error: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Watch().Recv() error: %v", err)
+	}
+	cancel()
+	if _, err := stream.Recv(); err == nil {
+		t.Fatal("Watch().Recv() after cancel error = nil, want error")
+	}
+
+	if got := testutil.ToFloat64(checkCounter) - checkBefore; got != 1 {
+		t.Errorf("client counter for Check increased by %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(watchCounter) - watchBefore; got != 1 {
+		t.Errorf("client counter for Watch increased by %v, want 1", got)
+	}
+
+	names := make(map[string]bool)
+	for _, span := range recorder.Ended() {
+		names[span.Name()] = true
+	}
+	for _, method := range []string{checkMethod, watchMethod} {
+		if !names[method] {
+			t.Errorf("span %q not ended, got %v", method, names)
+		}
+	}
+}
+
+func TestTraceStreamClientInterceptor_FakeStream(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	var fake *fakeClientStream
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		fake = newFakeClientStream(ctx)
+		fake.recv = func(m interface{}) error {
+			if fake.received == 2 {
+				return io.EOF
+			}
+			return nil
+		}
+		return fake, nil
+	}
+
+	desc := &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+	cs, err := TraceStreamClientInterceptor()(context.Background(), desc, nil, "/test.Service/Chat", streamer)
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	// 调用 span 通过包装后的 context 传给 streamer
+	if !oteltrace.SpanContextFromContext(fake.Context()).IsValid() {
+		t.Error("streamer context has no span context")
+	}
+
+	if err := cs.SendMsg(nil); err != nil {
+		t.Fatalf("SendMsg() error = %v", err)
+	}
+	for {
+		if err := cs.RecvMsg(nil); err != nil {
+			if err != io.EOF {
+				t.Fatalf("RecvMsg() error = %v, want io.EOF", err)
+			}
+			break
+		}
+	}
+
+	if fake.sent != 1 || fake.received != 2 {
+		t.Errorf("sent, received = %d, %d, want 1, 2", fake.sent, fake.received)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])["rpc.status_code"].AsString(); got != codes.OK.String() {
+		t.Errorf("rpc.status_code = %q, want %q", got, codes.OK.String())
+	}
+}
+
+func TestPerMethodDeadlineClientInterceptor(t *testing.T) {
+	interceptor := PerMethodDeadlineClientInterceptor(map[string]time.Duration{
+		"/test.Service/Slow":    5 * time.Second,
+		"/test.Service/NoLimit": 0,
+	}, time.Second)
+
+	existingCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	existing, _ := existingCtx.Deadline()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		method       string
+		wantDeadline bool
+		wantTimeout  time.Duration
+	}{
+		{"configured method", context.Background(), "/test.Service/Slow", true, 5 * time.Second},
+		{"fallback", context.Background(), "/test.Service/Other", true, time.Second},
+		{"zero disables deadline", context.Background(), "/test.Service/NoLimit", false, 0},
+		{"existing deadline kept", existingCtx, "/test.Service/Slow", true, time.Until(existing)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				deadline, hasDeadline = ctx.Deadline()
+				return nil
+			}
+			if err := interceptor(tt.ctx, tt.method, nil, nil, nil, invoker); err != nil {
+				t.Fatalf("interceptor() error = %v", err)
+			}
+			if hasDeadline != tt.wantDeadline {
+				t.Fatalf("has deadline = %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+			if !hasDeadline {
+				return
+			}
+			if got := time.Until(deadline); got > tt.wantTimeout || got < tt.wantTimeout-time.Second {
+				t.Errorf("remaining timeout = %v, want about %v", got, tt.wantTimeout)
+			}
+		})
+	}
+}
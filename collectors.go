@@ -0,0 +1,200 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// GRPCRequestRejectedTotal 被拦截器拒绝的 gRPC 请求总数
+	GRPCRequestRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_rejected_total",
+			Help: "Total number of gRPC requests rejected by interceptors",
+		},
+		[]string{"method", "reason"},
+	)
+
+	// GRPCClientRequestTotal gRPC 客户端调用总数
+	GRPCClientRequestTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_client_requests_total",
+			Help: "Total number of gRPC client calls",
+		},
+		[]string{"method", "code"},
+	)
+
+	// GRPCClientRequestDuration gRPC 客户端调用耗时（秒）
+	GRPCClientRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_client_request_duration_seconds",
+			Help:    "gRPC client call duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "code"},
+	)
+
+	// GRPCRetryBudgetExhaustedTotal 因重试预算耗尽而放弃重试的客户端调用总数
+	GRPCRetryBudgetExhaustedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_client_retry_budget_exhausted_total",
+			Help: "Total number of gRPC client retries skipped because the retry budget was exhausted",
+		},
+		[]string{"method"},
+	)
+
+	// GRPCTimeoutTotal 被 TimeoutUnaryInterceptor 强制超时的 gRPC 请求总数，不包含客户端 deadline 导致的超时
+	GRPCTimeoutTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_timeout_total",
+			Help: "Total number of gRPC requests cut off by the server-enforced timeout",
+		},
+		[]string{"method"},
+	)
+
+	// GRPCQueueDuration gRPC 请求在并发限制拦截器中等待执行的时间（秒）
+	GRPCQueueDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_request_queue_duration_seconds",
+			Help:    "Time gRPC requests spent waiting for a concurrency slot in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method"},
+	)
+
+	// GRPCAdaptiveConcurrencyLimit 自适应并发限制拦截器当前的并发限制
+	GRPCAdaptiveConcurrencyLimit = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "grpc_adaptive_concurrency_limit",
+			Help: "Current concurrency limit of the adaptive concurrency interceptor",
+		},
+	)
+
+	// GRPCAdaptiveConcurrencyInFlight 自适应并发限制拦截器中正在处理的请求数
+	GRPCAdaptiveConcurrencyInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "grpc_adaptive_concurrency_inflight",
+			Help: "Number of in-flight requests admitted by the adaptive concurrency interceptor",
+		},
+	)
+
+	// GRPCCostWindowCurrent 成本限制拦截器当前窗口内已累计的成本
+	GRPCCostWindowCurrent = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "grpc_cost_window_current",
+			Help: "Accumulated request cost in the current window of the cost limit interceptor",
+		},
+	)
+
+	// GRPCStreamMsgReceivedTotal 服务端流上成功接收的消息总数
+	GRPCStreamMsgReceivedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_stream_msg_received_total",
+			Help: "Total number of messages received on gRPC server streams",
+		},
+		[]string{"method"},
+	)
+
+	// GRPCStreamMsgSentTotal 服务端流上成功发送的消息总数
+	GRPCStreamMsgSentTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_stream_msg_sent_total",
+			Help: "Total number of messages sent on gRPC server streams",
+		},
+		[]string{"method"},
+	)
+
+	// GRPCStreamRecvInterval 服务端流上相邻两次成功接收消息的间隔（秒），用于发现发送缓慢的客户端
+	GRPCStreamRecvInterval = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_stream_recv_interval_seconds",
+			Help:    "Time between consecutive messages received on gRPC server streams in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method"},
+	)
+
+	// GRPCCoalescedTotal 被 SingleflightUnaryInterceptor 合并、复用其他请求结果的 gRPC 请求总数
+	GRPCCoalescedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_coalesced_total",
+			Help: "Total number of gRPC requests that shared the result of an identical in-flight request",
+		},
+		[]string{"method"},
+	)
+
+	// GRPCLiteRequestTotal LiteMetricsUnaryInterceptor 记录的 gRPC 请求总数，只按结果（ok、error）区分
+	GRPCLiteRequestTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_lite_requests_total",
+			Help: "Total number of gRPC requests by result, without per-method labels",
+		},
+		[]string{"result"},
+	)
+
+	// GRPCLiteRequestDuration LiteMetricsUnaryInterceptor 记录的 gRPC 请求耗时（秒），只按结果（ok、error）区分
+	GRPCLiteRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_lite_request_duration_seconds",
+			Help:    "gRPC request duration in seconds by result, without per-method labels",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"result"},
+	)
+
+	// GRPCShedTotal 被 PriorityUnaryInterceptor 按优先级丢弃的 gRPC 请求总数
+	GRPCShedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_shed_total",
+			Help: "Total number of gRPC requests shed by the priority interceptor",
+		},
+		[]string{"priority"},
+	)
+
+	// GRPCInterceptorDuration 单个服务端拦截器自身的耗时（秒），不包含其调用的后续拦截器和处理器，
+	// 仅在 DefaultUnaryServerInterceptors 启用 WithInterceptorTiming 时记录
+	GRPCInterceptorDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_interceptor_duration_seconds",
+			Help:    "Time spent in each gRPC server interceptor itself, excluding the rest of the chain, in seconds",
+			Buckets: []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1},
+		},
+		[]string{"interceptor"},
+	)
+
+	// GRPCMethodFirstSeen 每个方法在本进程内首次收到请求的 Unix 时间戳（秒），每个方法只设置一次
+	GRPCMethodFirstSeen = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "grpc_method_first_seen_timestamp_seconds",
+			Help: "Unix timestamp of the first gRPC request received for each method since process start",
+		},
+		[]string{"method"},
+	)
+
+	// GRPCRequestLatencySummary gRPC 请求耗时 Summary（秒），使用默认分位数目标，
+	// 仅在 metrics 拦截器使用默认 registry 并启用 Summary 时记录
+	GRPCRequestLatencySummary = promauto.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       "grpc_request_latency_summary_seconds",
+			Help:       "gRPC request latency summary in seconds",
+			Objectives: defaultSummaryObjectives,
+		},
+		[]string{"method", "code"},
+	)
+)
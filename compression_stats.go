@@ -0,0 +1,70 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc/stats"
+)
+
+// compressionKey 存放 CompressionStatsHandler 记录的请求压缩算法
+const compressionKey = contextKey("requestCompression")
+
+// requestCompression 请求使用的压缩算法，由 stats.Handler 在请求头到达时写入
+// gRPC 在分发请求之前处理 InHeader，写入先于拦截器读取，无需加锁
+type requestCompression struct {
+	name string
+}
+
+// CompressionStatsHandler 返回记录请求压缩算法的 stats.Handler，通过 grpc.StatsHandler 安装到服务端
+// grpc-encoding 是 gRPC 的保留 header，不会出现在 incoming metadata 中，
+// TraceUnaryInterceptor 依赖该 handler 在 span 上记录 rpc.grpc.request_compression，未安装时不记录
+func CompressionStatsHandler() stats.Handler {
+	return compressionStatsHandler{}
+}
+
+// compressionStatsHandler CompressionStatsHandler 的实现
+type compressionStatsHandler struct{}
+
+func (compressionStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, compressionKey, &requestCompression{})
+}
+
+func (compressionStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	in, ok := s.(*stats.InHeader)
+	if !ok || in.IsClient() {
+		return
+	}
+	if holder, ok := ctx.Value(compressionKey).(*requestCompression); ok {
+		holder.name = in.Compression
+	}
+}
+
+func (compressionStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (compressionStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// requestCompressionFromContext 返回 CompressionStatsHandler 记录的请求压缩算法，未安装 handler 时返回空字符串
+func requestCompressionFromContext(ctx context.Context) string {
+	if holder, ok := ctx.Value(compressionKey).(*requestCompression); ok {
+		return holder.name
+	}
+	return ""
+}
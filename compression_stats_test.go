@@ -0,0 +1,86 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestTraceUnaryInterceptor_CompressionAttribute(t *testing.T) {
+	tests := []struct {
+		name         string
+		statsHandler bool
+		callOpts     []grpc.CallOption
+		want         string
+	}{
+		{"gzip request", true, []grpc.CallOption{grpc.UseCompressor(gzip.Name)}, gzip.Name},
+		{"uncompressed request", true, nil, ""},
+		{"stats handler not installed", false, []grpc.CallOption{grpc.UseCompressor(gzip.Name)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := setupTestTracer(t)
+
+			serverOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(TraceUnaryInterceptor())}
+			if tt.statsHandler {
+				serverOpts = append(serverOpts, grpc.StatsHandler(CompressionStatsHandler()))
+			}
+			lis := bufconn.Listen(1 << 20)
+			srv := grpc.NewServer(serverOpts...)
+			healthpb.RegisterHealthServer(srv, health.NewServer())
+			go func() {
+				_ = srv.Serve(lis)
+			}()
+			t.Cleanup(srv.Stop)
+
+			conn, err := grpc.NewClient("passthrough:///bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return lis.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			if err != nil {
+				t.Fatalf("grpc.NewClient() error: %v", err)
+			}
+			t.Cleanup(func() {
+				_ = conn.Close()
+			})
+
+			if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}, tt.callOpts...); err != nil {
+				t.Fatalf("Check() error: %v", err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended spans = %d, want 1", len(spans))
+			}
+			got := spanAttributes(spans[0])[attribute.Key("rpc.grpc.request_compression")].AsString()
+			if got != tt.want {
+				t.Errorf("rpc.grpc.request_compression = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
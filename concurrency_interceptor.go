@@ -0,0 +1,55 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ConcurrencyLimitUnaryInterceptor 创建并发限制拦截器，同时处理的请求数不超过 limit
+// 超出的请求排队等待空闲槽位，等待时间记录到 GRPCQueueDuration，用于区分服务饱和与处理器变慢；
+// 等待期间 context 取消或超时时返回对应的 Canceled / DeadlineExceeded。
+// limit <= 0 时不限制并发，也不记录排队时间
+func ConcurrencyLimitUnaryInterceptor(limit int) grpc.UnaryServerInterceptor {
+	if limit <= 0 {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(ctx, req)
+		}
+	}
+
+	slots := make(chan struct{}, limit)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := fullMethodFromInfo(info)
+		start := nowFunc()
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			GRPCRequestRejectedTotal.WithLabelValues(method, "queue_timeout").Inc()
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		defer func() { <-slots }()
+
+		GRPCQueueDuration.WithLabelValues(method).Observe(since(start).Seconds())
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimitUnaryInterceptor_QueueDuration(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0), step: 2 * time.Second}
+	setNowFunc(t, clock.Now)
+
+	interceptor := ConcurrencyLimitUnaryInterceptor(1)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Limited",
+	}
+	t.Cleanup(func() {
+		GRPCQueueDuration.DeleteLabelValues(info.FullMethod)
+	})
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	expected := `
+# HELP grpc_request_queue_duration_seconds Time gRPC requests spent waiting for a concurrency slot in seconds
+# TYPE grpc_request_queue_duration_seconds histogram
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="0.005"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="0.01"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="0.025"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="0.05"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="0.1"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="0.25"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="0.5"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="1"} 0
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="2.5"} 1
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="5"} 1
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="10"} 1
+grpc_request_queue_duration_seconds_bucket{method="/test.Service/Limited",le="+Inf"} 1
+grpc_request_queue_duration_seconds_sum{method="/test.Service/Limited"} 2
+grpc_request_queue_duration_seconds_count{method="/test.Service/Limited"} 1
+`
+	if err := testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected), "grpc_request_queue_duration_seconds"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConcurrencyLimitUnaryInterceptor_WaitCanceled(t *testing.T) {
+	interceptor := ConcurrencyLimitUnaryInterceptor(1)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Busy",
+	}
+	t.Cleanup(func() {
+		GRPCQueueDuration.DeleteLabelValues(info.FullMethod)
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "response", nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	called := false
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return "response", nil
+	})
+
+	close(release)
+	<-done
+
+	if called {
+		t.Error("handler should not be called when the wait times out")
+	}
+	if got := status.Code(err); got != codes.DeadlineExceeded {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.DeadlineExceeded)
+	}
+}
+
+func TestConcurrencyLimitUnaryInterceptor_NoLimit(t *testing.T) {
+	before := testutil.CollectAndCount(GRPCQueueDuration)
+
+	interceptor := ConcurrencyLimitUnaryInterceptor(0)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Unlimited",
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	if got := testutil.CollectAndCount(GRPCQueueDuration); got != before {
+		t.Errorf("queue duration series = %d, want %d", got, before)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-anyway/framework-log"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type contextKey string
+
+const (
+	methodKey    = contextKey("method")
+	logFieldsKey = contextKey("logFields")
+	peerCNKey    = contextKey("peerCommonName")
+	tenantKey    = contextKey("tenant")
+	causationKey = contextKey("causationID")
+	spanLinksKey = contextKey("spanLinks")
+	debugKey     = contextKey("debug")
+)
+
+// contextWithMethod 返回一个包含 gRPC 方法名的新 context
+func contextWithMethod(ctx context.Context, fullMethod string) context.Context {
+	return context.WithValue(ctx, methodKey, fullMethod)
+}
+
+// MethodFromContext 从 context 中提取 gRPC 方法名
+func MethodFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if method, ok := ctx.Value(methodKey).(string); ok {
+		return method
+	}
+	return ""
+}
+
+// contextWithPeerCommonName 返回一个包含客户端证书 Common Name 的新 context
+func contextWithPeerCommonName(ctx context.Context, commonName string) context.Context {
+	return context.WithValue(ctx, peerCNKey, commonName)
+}
+
+// PeerCommonNameFromContext 从 context 中提取经 RequireMTLSUnaryInterceptor 验证的客户端证书 Common Name
+func PeerCommonNameFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if commonName, ok := ctx.Value(peerCNKey).(string); ok {
+		return commonName
+	}
+	return ""
+}
+
+// contextWithTenant 返回一个包含租户 ID 的新 context
+func contextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// TenantFromContext 从 context 中提取 TenantUnaryInterceptor 解析出的租户 ID
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if tenant, ok := ctx.Value(tenantKey).(string); ok {
+		return tenant
+	}
+	return ""
+}
+
+// contextWithCausationID 返回一个包含 causationID 的新 context
+func contextWithCausationID(ctx context.Context, causationID string) context.Context {
+	return context.WithValue(ctx, causationKey, causationID)
+}
+
+// CausationIDFromContext 从 context 中提取上游通过 x-causation-id 传入的 causationID，即引起本次请求的上游请求 ID
+func CausationIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if causationID, ok := ctx.Value(causationKey).(string); ok {
+		return causationID
+	}
+	return ""
+}
+
+// contextWithDebug 返回一个标记为调试请求的新 context
+func contextWithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugKey, true)
+}
+
+// DebugFromContext 判断当前请求是否携带 x-debug 调试标记，处理器可据此输出额外的调试日志
+func DebugFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	debug, _ := ctx.Value(debugKey).(bool)
+	return debug
+}
+
+// LinkSpans 返回一个携带 span 链接的新 context，在原有链接之后追加 links
+// 客户端 trace 拦截器创建调用 span 时会附加这些链接，可用于表达并发扇出调用之间的关系
+func LinkSpans(ctx context.Context, links ...oteltrace.Link) context.Context {
+	if len(links) == 0 {
+		return ctx
+	}
+	existing := spanLinksFromContext(ctx)
+	merged := make([]oteltrace.Link, 0, len(existing)+len(links))
+	merged = append(merged, existing...)
+	merged = append(merged, links...)
+	return context.WithValue(ctx, spanLinksKey, merged)
+}
+
+// spanLinksFromContext 返回通过 LinkSpans 放入 context 的 span 链接
+func spanLinksFromContext(ctx context.Context) []oteltrace.Link {
+	links, _ := ctx.Value(spanLinksKey).([]oteltrace.Link)
+	return links
+}
+
+// DetachContext 返回一个与请求生命周期解绑的新 context，用于处理器中启动的后台 goroutine
+// 新 context 携带 traceID、requestID、方法名和 span context，但不继承取消信号和超时，
+// RPC 返回后后台任务仍能保持日志和追踪的关联
+// 注意：只携带 span 的标识，不会延长原 span 的生命周期，后台任务应基于它创建自己的子 span
+func DetachContext(ctx context.Context) context.Context {
+	detached := context.Background()
+	if ctx == nil {
+		return detached
+	}
+
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		detached = oteltrace.ContextWithSpanContext(detached, sc)
+	}
+	if traceID := log.TraceIDFromContext(ctx); traceID != "" {
+		detached = log.ContextWithTraceID(detached, traceID)
+	}
+	if requestID := log.RequestIDFromContext(ctx); requestID != "" {
+		detached = log.ContextWithRequestID(detached, requestID)
+	}
+	if method := MethodFromContext(ctx); method != "" {
+		detached = contextWithMethod(detached, method)
+	}
+	return detached
+}
+
+// logFields 请求级别的日志字段容器，由 trace 拦截器在调用处理器前放入 context，
+// 处理器追加的字段对拦截器可见
+type logFields struct {
+	// method 请求的完整方法名，LoggerFromContext 构建 logger 时绑定
+	method string
+	mu     sync.Mutex
+	fields []zap.Field
+}
+
+// contextWithLogFields 返回一个包含空日志字段容器的新 context，method 在 LoggerFromContext 中绑定到 logger
+func contextWithLogFields(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, logFieldsKey, &logFields{method: method})
+}
+
+// AddLogFields 向当前请求追加结构化日志字段，这些字段会出现在 trace 拦截器的请求完成日志中
+// 字段保存在拦截器放入 context 的容器中，而不是派生的 logger 上，因此处理器内部派生的 context 也能生效；
+// context 中没有容器（未使用 TraceUnaryInterceptor）时不做任何操作。处理器中的典型用法：
+//
+//	interceptor.AddLogFields(ctx, zap.String("order_id", req.OrderId))
+//	logger := interceptor.LoggerFromContext(ctx)
+func AddLogFields(ctx context.Context, fields ...zap.Field) {
+	if ctx == nil {
+		return
+	}
+	holder, ok := ctx.Value(logFieldsKey).(*logFields)
+	if !ok {
+		return
+	}
+	holder.mu.Lock()
+	holder.fields = append(holder.fields, fields...)
+	holder.mu.Unlock()
+}
+
+// LogFieldsFromContext 返回当前请求通过 AddLogFields 追加的日志字段
+func LogFieldsFromContext(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	holder, ok := ctx.Value(logFieldsKey).(*logFields)
+	if !ok {
+		return nil
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	fields := make([]zap.Field, len(holder.fields))
+	copy(fields, holder.fields)
+	return fields
+}
+
+// LoggerFromContext 返回包含 traceID、requestID 以及通过 AddLogFields 追加字段的 logger
+// 经过 TraceUnaryInterceptor 的请求返回的 logger 还绑定了 method 字段，处理器无需重复添加公共字段；
+// logger 在调用时才构建，不调用的请求没有额外开销
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	logger := log.FromContext(ctx)
+	if ctx == nil {
+		return logger
+	}
+	if holder, ok := ctx.Value(logFieldsKey).(*logFields); ok && holder.method != "" {
+		logger = logger.With(zap.String("method", holder.method))
+	}
+	return withLogFields(logger, LogFieldsFromContext(ctx), 0)
+}
+
+// loggerFromContext 返回基于 log.FromContext 的 logger，追加的字段截断到 maxFieldLength 个字符，maxFieldLength <= 0 时不截断
+func loggerFromContext(ctx context.Context, maxFieldLength int) *zap.Logger {
+	return withLogFields(log.FromContext(ctx), LogFieldsFromContext(ctx), maxFieldLength)
+}
+
+// withLogFields 返回附加了 fields 的 logger，fields 为空时原样返回
+func withLogFields(logger *zap.Logger, fields []zap.Field, maxFieldLength int) *zap.Logger {
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(truncateFields(fields, maxFieldLength)...)
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestDetachContext(t *testing.T) {
+	traceID, _ := oteltrace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := oteltrace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: oteltrace.FlagsSampled,
+	})
+
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), sc)
+	ctx = log.ContextWithRequestID(ctx, "req-123")
+	ctx = contextWithMethod(ctx, "/test.Service/TestMethod")
+	ctx, cancel := context.WithCancel(ctx)
+
+	detached := DetachContext(ctx)
+	cancel()
+
+	if detached.Err() != nil {
+		t.Errorf("detached context Err() = %v, want nil after parent cancel", detached.Err())
+	}
+	if _, ok := detached.Deadline(); ok {
+		t.Error("detached context has a deadline, want none")
+	}
+	if got := oteltrace.SpanContextFromContext(detached); !got.Equal(sc) {
+		t.Errorf("span context = %v, want %v", got, sc)
+	}
+	if got := log.TraceIDFromContext(detached); got != traceID.String() {
+		t.Errorf("trace ID = %q, want %q", got, traceID.String())
+	}
+	if got := log.RequestIDFromContext(detached); got != "req-123" {
+		t.Errorf("request ID = %q, want %q", got, "req-123")
+	}
+	if got := MethodFromContext(detached); got != "/test.Service/TestMethod" {
+		t.Errorf("method = %q, want %q", got, "/test.Service/TestMethod")
+	}
+}
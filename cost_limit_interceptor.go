@@ -0,0 +1,86 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CostFunc 计算单个请求的成本，昂贵的方法可以返回更大的值
+type CostFunc func(fullMethod string, req interface{}) int
+
+// CostLimitUnaryInterceptor 创建基于请求成本的限流拦截器
+// 按固定时间窗口累计 costFn 计算出的请求成本，累计成本超过 maxPerWindow 时返回 ResourceExhausted，
+// 被拒绝的请求不计入成本。costFn 为 nil 时每个请求成本为 1。
+// 当前窗口的累计成本记录在 GRPCCostWindowCurrent，同一进程内使用多个成本限制拦截器时该指标会互相覆盖
+func CostLimitUnaryInterceptor(costFn CostFunc, maxPerWindow int, window time.Duration) grpc.UnaryServerInterceptor {
+	if costFn == nil {
+		costFn = func(string, interface{}) int { return 1 }
+	}
+	b := &costBudget{
+		max:    maxPerWindow,
+		window: window,
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := fullMethodFromInfo(info)
+		if !b.take(costFn(method, req)) {
+			GRPCRequestRejectedTotal.WithLabelValues(method, "cost_limit").Inc()
+			return nil, status.Error(codes.ResourceExhausted, "request cost budget exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// costBudget 固定窗口的成本预算
+type costBudget struct {
+	mu          sync.Mutex
+	max         int
+	window      time.Duration
+	windowStart time.Time
+	used        int
+}
+
+// take 在当前窗口的预算足够时扣除 cost 并返回 true
+func (b *costBudget) take(cost int) bool {
+	if cost < 0 {
+		cost = 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := nowFunc()
+	if b.windowStart.IsZero() || now.Sub(b.windowStart) >= b.window {
+		b.windowStart = now
+		b.used = 0
+	}
+
+	if b.used+cost > b.max {
+		GRPCCostWindowCurrent.Set(float64(b.used))
+		return false
+	}
+	b.used += cost
+	GRPCCostWindowCurrent.Set(float64(b.used))
+	return true
+}
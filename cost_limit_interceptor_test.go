@@ -0,0 +1,71 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCostLimitUnaryInterceptor(t *testing.T) {
+	now := time.Unix(0, 0)
+	setNowFunc(t, func() time.Time { return now })
+
+	costFn := func(fullMethod string, req interface{}) int {
+		if fullMethod == "/test.Service/Export" {
+			return 5
+		}
+		return 1
+	}
+	interceptor := CostLimitUnaryInterceptor(costFn, 7, time.Second)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	call := func(method string) codes.Code {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return status.Code(err)
+	}
+
+	steps := []struct {
+		method   string
+		advance  time.Duration
+		wantCode codes.Code
+		wantCost float64
+	}{
+		{method: "/test.Service/Export", wantCode: codes.OK, wantCost: 5},
+		{method: "/test.Service/Read", wantCode: codes.OK, wantCost: 6},
+		{method: "/test.Service/Export", wantCode: codes.ResourceExhausted, wantCost: 6},
+		{method: "/test.Service/Read", wantCode: codes.OK, wantCost: 7},
+		{method: "/test.Service/Read", wantCode: codes.ResourceExhausted, wantCost: 7},
+		// 进入新窗口后预算重置
+		{method: "/test.Service/Export", advance: time.Second, wantCode: codes.OK, wantCost: 5},
+	}
+
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if got := call(step.method); got != step.wantCode {
+			t.Errorf("step %d: code = %v, want %v", i, got, step.wantCode)
+		}
+		if got := testutil.ToFloat64(GRPCCostWindowCurrent); got != step.wantCost {
+			t.Errorf("step %d: window cost = %v, want %v", i, got, step.wantCost)
+		}
+	}
+}
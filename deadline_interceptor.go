@@ -0,0 +1,48 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RequireDeadlineUnaryInterceptor 创建 deadline 校验拦截器
+// 请求的 context 没有 deadline 时默认返回 InvalidArgument（可通过 WithMissingDeadlineCode 修改）并计入 GRPCRequestRejectedTotal，
+// 避免客户端忘记设置 deadline 导致请求无限占用资源。
+// 配置 WithDefaultDeadline 时改为为请求设置默认时限后放行，适用于逐步推广
+func RequireDeadlineUnaryInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = o.enterChain(ctx)
+		if _, ok := ctx.Deadline(); ok {
+			return handler(ctx, req)
+		}
+
+		if o.defaultDeadline <= 0 {
+			GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "missing_deadline").Inc()
+			return nil, status.Error(o.missingDeadlineCode, "request deadline required")
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, o.defaultDeadline)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequireDeadlineUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/NoDeadline",
+	}
+	counter := GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, "missing_deadline")
+	before := testutil.ToFloat64(counter)
+
+	var called int
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called++
+		return "ok", nil
+	}
+
+	_, err := RequireDeadlineUnaryInterceptor()(context.Background(), nil, info, handler)
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("code without deadline = %v, want %v", got, codes.InvalidArgument)
+	}
+	_, err = RequireDeadlineUnaryInterceptor(WithMissingDeadlineCode(codes.FailedPrecondition))(context.Background(), nil, info, handler)
+	if got := status.Code(err); got != codes.FailedPrecondition {
+		t.Errorf("code with custom code = %v, want %v", got, codes.FailedPrecondition)
+	}
+	if called != 0 {
+		t.Errorf("handler called %d times for rejected requests, want 0", called)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 2 {
+		t.Errorf("rejected counter increment = %v, want 2", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := RequireDeadlineUnaryInterceptor()(ctx, nil, info, handler); err != nil {
+		t.Errorf("interceptor() with deadline error = %v", err)
+	}
+	if called != 1 {
+		t.Errorf("handler called %d times, want 1", called)
+	}
+}
+
+func TestRequireDeadlineUnaryInterceptor_WithDefaultDeadline(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/DefaultDeadline",
+	}
+
+	var remaining time.Duration
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("handler context has no deadline")
+		}
+		remaining = time.Until(deadline)
+		return nil, nil
+	}
+
+	interceptor := RequireDeadlineUnaryInterceptor(WithDefaultDeadline(5 * time.Second))
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if remaining <= 0 || remaining > 5*time.Second {
+		t.Errorf("remaining = %v, want within (0, 5s]", remaining)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Drainer 控制 DrainUnaryInterceptor 的排空状态，并统计正在处理中的请求数
+type Drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	// drained 在排空开始且处理中的请求数降为 0 时关闭
+	drained chan struct{}
+}
+
+// DrainUnaryInterceptor 创建支持优雅关闭的拦截器
+// 调用返回的 Drainer.Drain() 后，新请求返回 Unavailable，已经在处理中的请求正常完成；
+// 通过 Drainer.Wait 可以等待处理中的请求全部结束后再退出进程
+func DrainUnaryInterceptor() (grpc.UnaryServerInterceptor, *Drainer) {
+	d := &Drainer{
+		drained: make(chan struct{}),
+	}
+
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !d.acquire() {
+			GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "draining").Inc()
+			return nil, status.Error(codes.Unavailable, "server draining")
+		}
+		defer d.release()
+
+		return handler(ctx, req)
+	}
+
+	return interceptor, d
+}
+
+// Drain 开始排空，之后的新请求都会被拒绝，可重复调用
+func (d *Drainer) Drain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return
+	}
+	d.draining = true
+	if d.inFlight == 0 {
+		close(d.drained)
+	}
+}
+
+// Draining 返回是否已经开始排空
+func (d *Drainer) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// InFlight 返回正在处理中的请求数
+func (d *Drainer) InFlight() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inFlight
+}
+
+// Wait 等待排空完成，即 Drain 已被调用且处理中的请求数降为 0
+// ctx 取消时返回 ctx.Err()
+func (d *Drainer) Wait(ctx context.Context) error {
+	select {
+	case <-d.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// acquire 在未排空时登记一个处理中的请求
+func (d *Drainer) acquire() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return false
+	}
+	d.inFlight++
+	return true
+}
+
+// release 请求处理结束，排空过程中最后一个请求结束时通知等待方
+func (d *Drainer) release() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight--
+	if d.draining && d.inFlight == 0 {
+		close(d.drained)
+	}
+}
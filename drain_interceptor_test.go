@@ -0,0 +1,94 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDrainUnaryInterceptor(t *testing.T) {
+	interceptor, drainer := DrainUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slowHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return "response", nil
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), nil, info, slowHandler)
+		errCh <- err
+	}()
+	<-started
+
+	if got := drainer.InFlight(); got != 1 {
+		t.Errorf("InFlight() = %d, want 1", got)
+	}
+
+	drainer.Drain()
+	if !drainer.Draining() {
+		t.Error("Draining() = false after Drain()")
+	}
+
+	// 排空后的新请求应被拒绝
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	if _, err := interceptor(context.Background(), nil, info, handler); status.Code(err) != codes.Unavailable {
+		t.Errorf("interceptor() after Drain() returned %v, want Unavailable", err)
+	}
+
+	// 处理中的请求未结束前 Wait 应超时
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := drainer.Wait(ctx); err == nil {
+		t.Error("Wait() returned nil while a request is still in flight")
+	}
+
+	close(release)
+	if err := <-errCh; err != nil {
+		t.Errorf("in-flight request returned unexpected error: %v", err)
+	}
+
+	if err := drainer.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() returned unexpected error: %v", err)
+	}
+	if got := drainer.InFlight(); got != 0 {
+		t.Errorf("InFlight() = %d, want 0", got)
+	}
+}
+
+func TestDrainer_DrainIdempotent(t *testing.T) {
+	_, drainer := DrainUnaryInterceptor()
+
+	drainer.Drain()
+	drainer.Drain()
+
+	if err := drainer.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() returned unexpected error: %v", err)
+	}
+}
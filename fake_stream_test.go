@@ -0,0 +1,143 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeServerStream 可编程的 grpc.ServerStream 实现，用于单元测试服务端流拦截器
+// recv、send 为 nil 时 RecvMsg、SendMsg 直接成功；stream 记录成功收发的消息数以及设置的 header、trailer
+type fakeServerStream struct {
+	ctx  context.Context
+	recv func(m interface{}) error
+	send func(m interface{}) error
+
+	mu       sync.Mutex
+	received int
+	sent     int
+	header   metadata.MD
+	trailer  metadata.MD
+}
+
+// newFakeServerStream 创建使用 ctx 的 fakeServerStream，ctx 为 nil 时使用 context.Background()
+func newFakeServerStream(ctx context.Context) *fakeServerStream {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &fakeServerStream{ctx: ctx}
+}
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeServerStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *fakeServerStream) SetTrailer(md metadata.MD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	if s.send != nil {
+		if err := s.send(m); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.sent++
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if s.recv != nil {
+		if err := s.recv(m); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.received++
+	s.mu.Unlock()
+	return nil
+}
+
+// fakeClientStream 可编程的 grpc.ClientStream 实现，用于单元测试客户端流拦截器
+// recv、send 为 nil 时 RecvMsg、SendMsg 直接成功
+type fakeClientStream struct {
+	ctx     context.Context
+	recv    func(m interface{}) error
+	send    func(m interface{}) error
+	header  metadata.MD
+	trailer metadata.MD
+
+	mu         sync.Mutex
+	received   int
+	sent       int
+	closedSend bool
+}
+
+// newFakeClientStream 创建使用 ctx 的 fakeClientStream，ctx 为 nil 时使用 context.Background()
+func newFakeClientStream(ctx context.Context) *fakeClientStream {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &fakeClientStream{ctx: ctx}
+}
+
+func (s *fakeClientStream) Header() (metadata.MD, error) { return s.header, nil }
+func (s *fakeClientStream) Trailer() metadata.MD         { return s.trailer }
+func (s *fakeClientStream) Context() context.Context     { return s.ctx }
+
+func (s *fakeClientStream) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closedSend = true
+	return nil
+}
+
+func (s *fakeClientStream) SendMsg(m interface{}) error {
+	if s.send != nil {
+		if err := s.send(m); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.sent++
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	if s.recv != nil {
+		if err := s.recv(m); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.received++
+	s.mu.Unlock()
+	return nil
+}
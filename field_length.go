@@ -0,0 +1,96 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// truncatedMarker 被截断的日志字段值末尾追加的标记
+const truncatedMarker = "...(truncated)"
+
+// truncateFields 将字符串、字节串和错误字段的值截断到 maxLen 个字符，maxLen <= 0 时原样返回
+// 错误字段会转换为同名的字符串字段；fields 不会被修改
+func truncateFields(fields []zap.Field, maxLen int) []zap.Field {
+	if maxLen <= 0 {
+		return fields
+	}
+
+	var out []zap.Field
+	for i, f := range fields {
+		truncated, ok := truncateField(f, maxLen)
+		if !ok {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zap.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, truncated)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// truncateField 截断单个字段，字段无需截断时返回 false
+func truncateField(f zap.Field, maxLen int) (zap.Field, bool) {
+	var value string
+	switch f.Type {
+	case zapcore.StringType:
+		value = f.String
+	case zapcore.ByteStringType:
+		b, _ := f.Interface.([]byte)
+		value = string(b)
+	case zapcore.ErrorType:
+		err, _ := f.Interface.(error)
+		if err == nil {
+			return f, false
+		}
+		value = err.Error()
+	default:
+		return f, false
+	}
+
+	if utf8.RuneCountInString(value) <= maxLen {
+		if f.Type == zapcore.ErrorType {
+			// 错误字段统一转换为字符串，避免 zap 额外输出不受长度限制的 errorVerbose
+			return zap.String(f.Key, value), true
+		}
+		return f, false
+	}
+	return zap.String(f.Key, truncateString(value, maxLen)), true
+}
+
+// truncateString 将 s 截断到 maxLen 个字符并追加截断标记
+func truncateString(s string, maxLen int) string {
+	n := 0
+	for i := range s {
+		if n == maxLen {
+			return s[:i] + truncatedMarker
+		}
+		n++
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTruncateFields(t *testing.T) {
+	fields := []zap.Field{
+		zap.Int("count", 3),
+		zap.String("short", "abc"),
+		zap.String("long", "abcdefgh"),
+		zap.String("unicode", "日志字段超长"),
+		zap.ByteString("bytes", []byte("0123456789")),
+		zap.Error(errors.New("something went wrong")),
+	}
+
+	got := truncateFields(fields, 5)
+	want := map[string]string{
+		"short":   "abc",
+		"long":    "abcde" + truncatedMarker,
+		"unicode": "日志字段超" + truncatedMarker,
+		"bytes":   "01234" + truncatedMarker,
+		"error":   "somet" + truncatedMarker,
+	}
+	for _, f := range got {
+		if f.Key == "count" {
+			if f.Integer != 3 {
+				t.Errorf("count = %d, want 3", f.Integer)
+			}
+			continue
+		}
+		if f.String != want[f.Key] {
+			t.Errorf("%s = %q, want %q", f.Key, f.String, want[f.Key])
+		}
+	}
+	if fields[2].String != "abcdefgh" {
+		t.Error("truncateFields modified its input")
+	}
+	if got := truncateFields(fields, 0); &got[0] != &fields[0] {
+		t.Error("truncateFields(0) should return the input unchanged")
+	}
+}
+
+func TestTraceUnaryInterceptor_WithMaxFieldLength(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor(WithMaxFieldLength(16))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		AddLogFields(ctx, zap.String("note", strings.Repeat("x", 100)))
+		return nil, status.Error(codes.Internal, strings.Repeat("y", 100))
+	})
+
+	entry := findLog(readLogs(), "gRPC request failed")
+	if entry == nil {
+		t.Fatal("failure log not found")
+	}
+	for _, key := range []string{"error", "note"} {
+		value, _ := entry[key].(string)
+		if !strings.HasSuffix(value, truncatedMarker) || len(value) != 16+len(truncatedMarker) {
+			t.Errorf("%s = %q, want 16 characters followed by %q", key, value, truncatedMarker)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// seenMethods 本进程内已经收到过请求的方法，所有 FirstSeenUnaryInterceptor 共享，保证每个方法只设置一次 GRPCMethodFirstSeen
+var seenMethods sync.Map
+
+// FirstSeenUnaryInterceptor 创建方法首次请求时间拦截器
+// 每个方法在本进程内第一次被调用时，将当前时间戳写入 GRPCMethodFirstSeen，之后的请求不再更新，
+// 便于将部署时间与流量变化对应起来。方法标签与 metrics 拦截器一致，默认跳过健康检查和反射请求
+func FirstSeenUnaryInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = o.enterChain(ctx)
+		method := fullMethodFromInfo(info)
+		if !o.skipMetrics(method) {
+			markFirstSeen(o.methodLabel(method))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// markFirstSeen 方法第一次出现时记录当前时间戳
+func markFirstSeen(method string) {
+	if _, seen := seenMethods.Load(method); seen {
+		return
+	}
+	if _, loaded := seenMethods.LoadOrStore(method, struct{}{}); !loaded {
+		GRPCMethodFirstSeen.WithLabelValues(method).Set(float64(nowFunc().UnixNano()) / 1e9)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+)
+
+func TestFirstSeenUnaryInterceptor(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	setNowFunc(t, func() time.Time { return now })
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.FirstSeen/Method",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	interceptor := FirstSeenUnaryInterceptor()
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	gauge := GRPCMethodFirstSeen.WithLabelValues(info.FullMethod)
+	if got := testutil.ToFloat64(gauge); got != 1700000000 {
+		t.Errorf("first seen = %v, want 1700000000", got)
+	}
+
+	// 后续请求（包括其他拦截器实例）不更新首次时间
+	now = now.Add(time.Hour)
+	if _, err := FirstSeenUnaryInterceptor()(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if got := testutil.ToFloat64(gauge); got != 1700000000 {
+		t.Errorf("first seen after second call = %v, want 1700000000", got)
+	}
+}
+
+func TestFirstSeenUnaryInterceptor_SkipsHealthChecks(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/grpc.health.v1.Health/Check",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	if _, err := FirstSeenUnaryInterceptor()(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if _, seen := seenMethods.Load(info.FullMethod); seen {
+		t.Error("health check method marked as seen")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHTTPStatusFromError(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.OK, http.StatusOK},
+		{codes.Canceled, 499},
+		{codes.Unknown, http.StatusInternalServerError},
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
+		{codes.NotFound, http.StatusNotFound},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.ResourceExhausted, http.StatusTooManyRequests},
+		{codes.FailedPrecondition, http.StatusBadRequest},
+		{codes.Aborted, http.StatusConflict},
+		{codes.OutOfRange, http.StatusBadRequest},
+		{codes.Unimplemented, http.StatusNotImplemented},
+		{codes.Internal, http.StatusInternalServerError},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.DataLoss, http.StatusInternalServerError},
+		{codes.Unauthenticated, http.StatusUnauthorized},
+		{codes.Code(100), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := HTTPStatusFromCode(tt.code); got != tt.want {
+				t.Errorf("HTTPStatusFromCode(%v) = %d, want %d", tt.code, got, tt.want)
+			}
+			if tt.code == codes.OK {
+				return
+			}
+			if got := HTTPStatusFromError(status.Error(tt.code, "error")); got != tt.want {
+				t.Errorf("HTTPStatusFromError(%v) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPStatusFromError_NonStatus(t *testing.T) {
+	if got := HTTPStatusFromError(nil); got != http.StatusOK {
+		t.Errorf("HTTPStatusFromError(nil) = %d, want %d", got, http.StatusOK)
+	}
+	if got := HTTPStatusFromError(errors.New("boom")); got != http.StatusInternalServerError {
+		t.Errorf("HTTPStatusFromError(plain error) = %d, want %d", got, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// idempotencyKeyHeader 客户端携带幂等键的 metadata key
+const idempotencyKeyHeader = "x-idempotency-key"
+
+// IdempotencyStore 幂等键存储，可以基于 Redis 或内存实现
+type IdempotencyStore interface {
+	// Get 返回 key 对应的缓存响应，found 表示 key 在有效期内存在
+	Get(ctx context.Context, key string) (resp interface{}, found bool, err error)
+	// Set 保存 key 对应的响应，ttl 后过期
+	Set(ctx context.Context, key string, resp interface{}, ttl time.Duration) error
+}
+
+// IdempotencyUnaryInterceptor 创建幂等拦截器
+// 读取 x-idempotency-key，若该键在 ttl 内已成功处理过，直接返回缓存的响应（无缓存响应时返回 AlreadyExists），
+// 不再执行处理器；未携带幂等键的请求正常处理。只有处理成功的响应会被缓存，
+// 幂等键按方法隔离，并发的相同请求可能都会执行处理器
+func IdempotencyUnaryInterceptor(store IdempotencyStore, ttl time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		idempotencyKey := lookupMetadata(md, idempotencyKeyHeader)
+		if idempotencyKey == "" {
+			return handler(ctx, req)
+		}
+
+		method := fullMethodFromInfo(info)
+		key := method + ":" + idempotencyKey
+
+		resp, found, err := store.Get(ctx, key)
+		if err != nil {
+			// 存储不可用时降级为正常处理
+			log.FromContext(ctx).Warn("Failed to get idempotency key",
+				zap.String("method", method),
+				zap.String("idempotency_key", idempotencyKey),
+				zap.Error(err),
+			)
+		} else if found {
+			if resp == nil {
+				return nil, status.Errorf(codes.AlreadyExists, "request with idempotency key %q already processed", idempotencyKey)
+			}
+			return resp, nil
+		}
+
+		resp, err = handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		if setErr := store.Set(ctx, key, resp, ttl); setErr != nil {
+			log.FromContext(ctx).Warn("Failed to set idempotency key",
+				zap.String("method", method),
+				zap.String("idempotency_key", idempotencyKey),
+				zap.Error(setErr),
+			)
+		}
+		return resp, nil
+	}
+}
+
+// memoryIdempotencyEntry 内存幂等存储中的条目
+type memoryIdempotencyEntry struct {
+	resp      interface{}
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore 基于内存的幂等键存储，适用于单实例部署和测试
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+}
+
+// NewMemoryIdempotencyStore 创建基于内存的幂等键存储
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]memoryIdempotencyEntry),
+	}
+}
+
+// Get 返回 key 对应的缓存响应，过期的条目会被删除
+func (s *MemoryIdempotencyStore) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if nowFunc().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.resp, true, nil
+}
+
+// Set 保存 key 对应的响应，同时清理已过期的条目
+func (s *MemoryIdempotencyStore) Set(ctx context.Context, key string, resp interface{}, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := nowFunc()
+	for k, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = memoryIdempotencyEntry{
+		resp:      resp,
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
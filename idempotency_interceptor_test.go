@@ -0,0 +1,127 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestIdempotencyUnaryInterceptor(t *testing.T) {
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Create",
+	}
+
+	var calls int
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-idempotency-key", "key-1"))
+	first, err := interceptor(ctx, nil, info, handler)
+	if err != nil {
+		t.Fatalf("first call returned unexpected error: %v", err)
+	}
+	second, err := interceptor(ctx, nil, info, handler)
+	if err != nil {
+		t.Fatalf("second call returned unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+	if first != second {
+		t.Errorf("second response = %v, want cached %v", second, first)
+	}
+
+	// 不携带幂等键的请求总是执行处理器
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("call without key returned unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestIdempotencyUnaryInterceptor_ErrorsNotCached(t *testing.T) {
+	interceptor := IdempotencyUnaryInterceptor(NewMemoryIdempotencyStore(), time.Minute)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Create",
+	}
+
+	var calls int
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, errors.New("temporary failure")
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-idempotency-key", "key-1"))
+	_, _ = interceptor(ctx, nil, info, handler)
+	_, _ = interceptor(ctx, nil, info, handler)
+
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2 since errors are not cached", calls)
+	}
+}
+
+func TestIdempotencyUnaryInterceptor_AlreadyExists(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	interceptor := IdempotencyUnaryInterceptor(store, time.Minute)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-idempotency-key", "key-1"))
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("first call returned unexpected error: %v", err)
+	}
+	if _, err := interceptor(ctx, nil, info, handler); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("second call returned %v, want AlreadyExists", err)
+	}
+}
+
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	setNowFunc(t, func() time.Time { return now })
+
+	store := NewMemoryIdempotencyStore()
+	ctx := context.Background()
+
+	if err := store.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	now = now.Add(time.Minute - time.Second)
+	if _, found, _ := store.Get(ctx, "key"); !found {
+		t.Error("Get() did not find key before expiry")
+	}
+
+	now = now.Add(2 * time.Second)
+	if _, found, _ := store.Get(ctx, "key"); found {
+		t.Error("Get() found expired key")
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// unknownMethod 无法获取方法名时使用的默认值
+	unknownMethod = "unknown"
+	// unknownLabelValue 指标标签值缺失或不在允许范围内时使用的默认值
+	unknownLabelValue = "unknown"
+)
+
+// healthCheckMethods 默认不计入 metrics 的健康检查方法，避免探针请求扭曲 QPS
+var healthCheckMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// reflectionMethodPrefix gRPC 服务反射的方法前缀，按前缀匹配以覆盖 v1、v1alpha 等多个版本
+const reflectionMethodPrefix = "/grpc.reflection."
+
+// nowFunc 返回当前时间，拦截器统一通过它计时，测试中可以替换为确定的时钟
+var nowFunc = time.Now
+
+// errNilHandler 拦截器链配置错误导致 handler 为 nil 时返回的错误
+var errNilHandler = status.Error(codes.Internal, "interceptor: nil handler, check interceptor chain configuration")
+
+// fullMethodFromInfo 从 UnaryServerInfo 中获取完整方法名，info 为 nil 时返回 unknownMethod
+func fullMethodFromInfo(info *grpc.UnaryServerInfo) string {
+	if info == nil || info.FullMethod == "" {
+		return unknownMethod
+	}
+	return info.FullMethod
+}
+
+// fullMethodFromStreamInfo 返回流请求的完整方法名，info 为 nil 或方法名为空时返回 unknown
+func fullMethodFromStreamInfo(info *grpc.StreamServerInfo) string {
+	if info == nil || info.FullMethod == "" {
+		return unknownMethod
+	}
+	return info.FullMethod
+}
+
+// isHealthCheckMethod 判断方法是否为 gRPC 健康检查方法
+func isHealthCheckMethod(method string) bool {
+	for _, m := range healthCheckMethods {
+		if method == m {
+			return true
+		}
+	}
+	return false
+}
+
+// isReflectionMethod 判断方法是否为 gRPC 服务反射方法
+func isReflectionMethod(method string) bool {
+	return strings.HasPrefix(method, reflectionMethodPrefix)
+}
+
+// since 返回自 start 以来经过的时间，与 time.Since 相同但使用 nowFunc
+func since(start time.Time) time.Duration {
+	return nowFunc().Sub(start)
+}
@@ -16,11 +16,42 @@ package interceptor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-anyway/framework-log"
+	"github.com/go-anyway/framework-metrics"
+	"github.com/go-anyway/framework-trace"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 func TestMetadataCarrier_Get(t *testing.T) {
@@ -86,6 +117,49 @@ func TestMetadataCarrier_Set(t *testing.T) {
 	}
 }
 
+func TestMetadataCarrier_SetNoDuplicates(t *testing.T) {
+	setupTestTracer(t)
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(prev)
+	})
+
+	// 上游手动构造的 metadata 中可能已经包含大小写不规范的 traceparent
+	md := metadata.MD{"Traceparent": []string{"00-stale-stale-01"}}
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	client := TraceUnaryClientInterceptor()
+	err := client(ctx, "/test.Service/TestMethod", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		// 嵌套的客户端拦截器会再次注入
+		return client(ctx, method, req, reply, cc, invoker, opts...)
+	})
+	if err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	if got := outgoing.Get("traceparent"); len(got) != 1 || strings.Contains(got[0], "stale") {
+		t.Errorf("traceparent = %v, want a single fresh value", got)
+	}
+
+	carrier := metadataCarrier(metadata.MD{"Traceparent": []string{"old"}})
+	carrier.Set("traceparent", "new")
+	carrier.Set("TRACEPARENT", "newer")
+	if len(carrier) != 1 || carrier.Get("traceparent") != "newer" {
+		t.Errorf("carrier after Set = %v, want only traceparent=newer", metadata.MD(carrier))
+	}
+}
+
 func TestMetadataCarrier_Keys(t *testing.T) {
 	md := metadata.New(map[string]string{
 		"key1": "value1",
@@ -113,20 +187,96 @@ func TestMetadataCarrier_Keys(t *testing.T) {
 	}
 }
 
+func TestMetadataCarrier_KeysSkipsBinaryAndPseudoHeaders(t *testing.T) {
+	md := metadata.MD{
+		"baggage":                 []string{"k=v"},
+		"grpc-status-details-bin": []string{"\x00\x01"},
+		"x-custom-bin":            []string{"\xff"},
+		":authority":              []string{"localhost"},
+		"traceparent":             []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}
+
+	keys := metadataCarrier(md).Keys()
+	sort.Strings(keys)
+	want := []string{"baggage", "traceparent"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("Keys() = %v, want %v", keys, want)
+	}
+}
+
+func TestNewMetadataCarrier(t *testing.T) {
+	prop := propagation.TraceContext{}
+	traceID, _ := oteltrace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := oteltrace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: oteltrace.FlagsSampled,
+	}))
+
+	md := metadata.MD{}
+	prop.Inject(ctx, NewMetadataCarrier(md))
+	if got := md.Get("traceparent"); len(got) != 1 {
+		t.Fatalf("traceparent = %v, want one value", got)
+	}
+
+	extracted := oteltrace.SpanContextFromContext(prop.Extract(context.Background(), NewMetadataCarrier(md)))
+	if extracted.TraceID() != traceID || extracted.SpanID() != spanID {
+		t.Errorf("extracted span context = %s/%s, want %s/%s", extracted.TraceID(), extracted.SpanID(), traceID, spanID)
+	}
+}
+
 func TestGenerateRequestID(t *testing.T) {
-	id1 := generateRequestID()
-	id2 := generateRequestID()
+	tests := []struct {
+		name    string
+		format  RequestIDFormat
+		pattern *regexp.Regexp
+	}{
+		{"default", 0, regexp.MustCompile(`^[0-9a-f]{32}$`)},
+		{"hex", RequestIDHex, regexp.MustCompile(`^[0-9a-f]{32}$`)},
+		{"uuid", RequestIDUUID, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{"base62", RequestIDBase62, regexp.MustCompile(`^[0-9A-Za-z]{22}$`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id1 := generateRequestID(tt.format)
+			id2 := generateRequestID(tt.format)
+
+			if !tt.pattern.MatchString(id1) {
+				t.Errorf("generateRequestID() = %q, want match %s", id1, tt.pattern)
+			}
+			if id1 == id2 {
+				t.Error("generateRequestID() returned same ID for two calls")
+			}
+		})
+	}
+}
 
-	if id1 == "" {
-		t.Error("generateRequestID() returned empty string")
+func TestTraceUnaryInterceptor_WithRequestIDFormat(t *testing.T) {
+	interceptor := TraceUnaryInterceptor(WithRequestIDFormat(RequestIDUUID))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
 	}
 
-	if len(id1) != 32 {
-		t.Errorf("generateRequestID() returned string of length %d, want 32", len(id1))
+	var requestID string
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		requestID = log.RequestIDFromContext(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if len(requestID) != 36 || strings.Count(requestID, "-") != 4 {
+		t.Errorf("requestID = %q, want UUID format", requestID)
 	}
+}
 
-	if id1 == id2 {
-		t.Error("generateRequestID() returned same ID for two calls")
+func TestEncodeBase62(t *testing.T) {
+	if got := encodeBase62(make([]byte, 16)); got != strings.Repeat("0", 22) {
+		t.Errorf("encodeBase62(zero) = %q, want 22 zeros", got)
+	}
+	if got := encodeBase62([]byte{61}); got != strings.Repeat("0", 21)+"z" {
+		t.Errorf("encodeBase62(61) = %q, want trailing z", got)
 	}
 }
 
@@ -243,3 +393,2500 @@ func TestMetricsUnaryInterceptor_WithError(t *testing.T) {
 		t.Errorf("interceptor() returned error %v, want %v", err, expectedErr)
 	}
 }
+
+func TestLookupMetadata_MissDoesNotAllocate(t *testing.T) {
+	md := metadata.Pairs("content-type", "application/grpc", "user-agent", "grpc-go")
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = lookupMetadata(md, "x-request-id")
+	})
+	if allocs != 0 {
+		t.Errorf("allocs per missing lookup = %v, want 0", allocs)
+	}
+}
+
+func TestLookupMetadata_Normalization(t *testing.T) {
+	tests := []struct {
+		name string
+		md   metadata.MD
+		key  string
+		want string
+	}{
+		{
+			name: "lowercase key",
+			md:   metadata.Pairs("x-request-id", "req-1"),
+			key:  "X-Request-ID",
+			want: "req-1",
+		},
+		{
+			name: "mixed case header normalized by metadata",
+			md:   metadata.Pairs("X-Request-ID", "req-2"),
+			key:  "x-request-id",
+			want: "req-2",
+		},
+		{
+			name: "grpc-web prefixed key",
+			md:   metadata.MD{"grpcweb-x-request-id": []string{"req-3"}},
+			key:  "x-request-id",
+			want: "req-3",
+		},
+		{
+			name: "unprefixed key preferred",
+			md: metadata.MD{
+				"grpcweb-x-request-id": []string{"prefixed"},
+				"x-request-id":         []string{"plain"},
+			},
+			key:  "x-request-id",
+			want: "plain",
+		},
+		{
+			name: "grpc-gateway prefixed key",
+			md:   metadata.MD{"grpcgateway-traceparent": []string{"00-gateway"}},
+			key:  "traceparent",
+			want: "00-gateway",
+		},
+		{
+			name: "unprefixed key preferred over grpc-gateway prefix",
+			md: metadata.MD{
+				"grpcgateway-x-request-id": []string{"prefixed"},
+				"x-request-id":             []string{"plain"},
+			},
+			key:  "x-request-id",
+			want: "plain",
+		},
+		{
+			name: "missing key",
+			md:   metadata.Pairs("x-other", "value"),
+			key:  "x-request-id",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lookupMetadata(tt.md, tt.key); got != tt.want {
+				t.Errorf("lookupMetadata(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+			if got := metadataCarrier(tt.md).Get(tt.key); got != tt.want {
+				t.Errorf("metadataCarrier.Get(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceUnaryInterceptor_MixedCaseRequestID(t *testing.T) {
+	interceptor := TraceUnaryInterceptor()
+
+	var gotRequestID string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		gotRequestID = log.RequestIDFromContext(ctx)
+		return "response", nil
+	}
+
+	md := metadata.MD{"X-Request-ID": []string{"browser-request"}}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	if gotRequestID != "browser-request" {
+		t.Errorf("request ID = %q, want %q", gotRequestID, "browser-request")
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithObserver(t *testing.T) {
+	var (
+		called     bool
+		gotMethod  string
+		gotCtxName string
+		gotTraceID string
+		gotErr     error
+	)
+	observer := func(ctx context.Context, fullMethod string, duration time.Duration, err error) {
+		called = true
+		gotMethod = fullMethod
+		gotCtxName = MethodFromContext(ctx)
+		gotTraceID = log.TraceIDFromContext(ctx)
+		gotErr = err
+	}
+
+	interceptor := MetricsUnaryInterceptor(WithObserver(observer))
+
+	expectedErr := errors.New("boom")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, expectedErr
+	}
+
+	ctx := log.ContextWithTraceID(context.Background(), "trace-123")
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	_, _ = interceptor(ctx, nil, info, handler)
+
+	if !called {
+		t.Fatal("observer was not called")
+	}
+	if gotMethod != info.FullMethod || gotCtxName != info.FullMethod {
+		t.Errorf("observer method = %q (ctx %q), want %q", gotMethod, gotCtxName, info.FullMethod)
+	}
+	if gotTraceID != "trace-123" {
+		t.Errorf("observer trace ID = %q, want %q", gotTraceID, "trace-123")
+	}
+	if !errors.Is(gotErr, expectedErr) {
+		t.Errorf("observer error = %v, want %v", gotErr, expectedErr)
+	}
+}
+
+func TestInterceptors_NilHandler(t *testing.T) {
+	interceptors := map[string]grpc.UnaryServerInterceptor{
+		"trace":   TraceUnaryInterceptor(),
+		"metrics": MetricsUnaryInterceptor(),
+	}
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	for name, interceptor := range interceptors {
+		t.Run(name, func(t *testing.T) {
+			_, err := interceptor(context.Background(), nil, info, nil)
+			if status.Code(err) != codes.Internal {
+				t.Errorf("interceptor() with nil handler returned %v, want code %v", err, codes.Internal)
+			}
+		})
+	}
+}
+
+func TestInterceptors_NilInfo(t *testing.T) {
+	interceptors := map[string]grpc.UnaryServerInterceptor{
+		"trace":   TraceUnaryInterceptor(),
+		"metrics": MetricsUnaryInterceptor(),
+	}
+
+	for name, interceptor := range interceptors {
+		t.Run(name, func(t *testing.T) {
+			var handlerCalled bool
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCalled = true
+				return "response", nil
+			}
+
+			_, err := interceptor(context.Background(), nil, nil, handler)
+			if err != nil {
+				t.Errorf("interceptor() with nil info returned unexpected error: %v", err)
+			}
+			if !handlerCalled {
+				t.Error("handler was not called")
+			}
+		})
+	}
+}
+
+func TestTraceUnaryInterceptor_WithRequireTrace(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	interceptor := TraceUnaryInterceptor(WithRequireTrace(true))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{
+			name:     "no trace context",
+			md:       metadata.Pairs("x-request-id", "req-1"),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "w3c traceparent",
+			md:       metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "custom trace header",
+			md:       metadata.Pairs("x-trace-id", "trace-123"),
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := interceptor(ctx, nil, info, handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+// setupTestTracer 设置一个记录 span 的全局 TracerProvider，测试结束后恢复
+func setupTestTracer(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+
+	return recorder
+}
+
+// spanAttributes 将 span 的属性转换为 map 便于断言
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTraceInterceptors_WithSpanAttributes(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	opt := WithSpanAttributes(
+		attribute.String("service.version", "1.2.3"),
+		attribute.String("deployment.environment", "test"),
+	)
+
+	server := TraceUnaryInterceptor(opt)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	if _, err := server(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("server interceptor returned unexpected error: %v", err)
+	}
+
+	client := TraceUnaryClientInterceptor(opt)
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	if err := client(context.Background(), "/test.Service/TestMethod", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("client interceptor returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+
+	for _, span := range spans {
+		attrs := spanAttributes(span)
+		if got := attrs["service.version"].AsString(); got != "1.2.3" {
+			t.Errorf("span %q service.version = %q, want %q", span.Name(), got, "1.2.3")
+		}
+		if got := attrs["deployment.environment"].AsString(); got != "test" {
+			t.Errorf("span %q deployment.environment = %q, want %q", span.Name(), got, "test")
+		}
+		if got := attrs["rpc.method"].AsString(); got != "/test.Service/TestMethod" {
+			t.Errorf("span %q rpc.method = %q, want built-in attribute preserved", span.Name(), got)
+		}
+	}
+}
+
+func TestTraceUnaryInterceptor_WithSpanAttributeFunc(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	interceptor := TraceUnaryInterceptor(WithSpanAttributeFunc(func(ctx context.Context, req interface{}) []attribute.KeyValue {
+		md, _ := metadata.FromIncomingContext(ctx)
+		return []attribute.KeyValue{attribute.String("tenant.id", lookupMetadata(md, "x-tenant-id"))}
+	}))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("handler failed")
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "tenant-a"))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	if _, err := interceptor(ctx, nil, info, handler); err == nil {
+		t.Fatal("interceptor() returned nil error, want handler error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])["tenant.id"].AsString(); got != "tenant-a" {
+		t.Errorf("tenant.id = %q, want %q", got, "tenant-a")
+	}
+}
+
+func TestMetricsUnaryInterceptor_HealthChecks(t *testing.T) {
+	const method = "/grpc.health.v1.Health/Check"
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantInc float64
+	}{
+		{
+			name:    "excluded by default",
+			wantInc: 0,
+		},
+		{
+			name:    "included when enabled",
+			opts:    []Option{WithIncludeHealthChecks(true)},
+			wantInc: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := MetricsUnaryInterceptor(tt.opts...)
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "response", nil
+			}
+			info := &grpc.UnaryServerInfo{
+				FullMethod: method,
+			}
+
+			counter := metrics.GRPCRequestTotal.WithLabelValues(method, codes.OK.String())
+			before := testutil.ToFloat64(counter)
+
+			if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+				t.Fatalf("interceptor() returned unexpected error: %v", err)
+			}
+
+			if got := testutil.ToFloat64(counter) - before; got != tt.wantInc {
+				t.Errorf("request counter increased by %v, want %v", got, tt.wantInc)
+			}
+		})
+	}
+}
+
+func TestInterceptors_Reflection(t *testing.T) {
+	const method = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantInc   float64
+		wantSpans int
+	}{
+		{
+			name:      "excluded by default",
+			wantInc:   0,
+			wantSpans: 0,
+		},
+		{
+			name:      "included when enabled",
+			opts:      []Option{WithIncludeReflection(true)},
+			wantInc:   1,
+			wantSpans: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := setupTestTracer(t)
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "response", nil
+			}
+			info := &grpc.UnaryServerInfo{
+				FullMethod: method,
+			}
+
+			counter := metrics.GRPCRequestTotal.WithLabelValues(method, codes.OK.String())
+			before := testutil.ToFloat64(counter)
+
+			if _, err := MetricsUnaryInterceptor(tt.opts...)(context.Background(), nil, info, handler); err != nil {
+				t.Fatalf("metrics interceptor returned unexpected error: %v", err)
+			}
+			if _, err := TraceUnaryInterceptor(tt.opts...)(context.Background(), nil, info, handler); err != nil {
+				t.Fatalf("trace interceptor returned unexpected error: %v", err)
+			}
+
+			if got := testutil.ToFloat64(counter) - before; got != tt.wantInc {
+				t.Errorf("request counter increased by %v, want %v", got, tt.wantInc)
+			}
+			if got := len(recorder.Ended()); got != tt.wantSpans {
+				t.Errorf("ended spans = %d, want %d", got, tt.wantSpans)
+			}
+		})
+	}
+}
+
+// fakeServerTransportStream 用于测试 header/trailer 的 grpc.ServerTransportStream 实现
+type fakeServerTransportStream struct {
+	method  string
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *fakeServerTransportStream) Method() string {
+	return s.method
+}
+
+func (s *fakeServerTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeServerTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *fakeServerTransportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestTraceUnaryInterceptor_WithTraceTrailers(t *testing.T) {
+	setupTestTracer(t)
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantTrailer bool
+	}{
+		{
+			name:        "disabled by default",
+			wantTrailer: false,
+		},
+		{
+			name:        "enabled",
+			opts:        []Option{WithTraceTrailers(true)},
+			wantTrailer: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := TraceUnaryInterceptor(tt.opts...)
+
+			var wantTraceID, wantSpanID string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				wantTraceID = trace.TraceIDFromContext(ctx)
+				wantSpanID = trace.SpanIDFromContext(ctx)
+				return "response", nil
+			}
+
+			stream := &fakeServerTransportStream{method: "/test.Service/TestMethod"}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			info := &grpc.UnaryServerInfo{
+				FullMethod: "/test.Service/TestMethod",
+			}
+
+			if _, err := interceptor(ctx, nil, info, handler); err != nil {
+				t.Fatalf("interceptor() returned unexpected error: %v", err)
+			}
+
+			gotTraceID := lookupMetadata(stream.trailer, "x-trace-id")
+			gotSpanID := lookupMetadata(stream.trailer, "x-span-id")
+			if !tt.wantTrailer {
+				if len(stream.trailer) != 0 {
+					t.Errorf("trailer = %v, want empty", stream.trailer)
+				}
+				return
+			}
+			if gotTraceID != wantTraceID || gotTraceID == "" {
+				t.Errorf("trailer x-trace-id = %q, want %q", gotTraceID, wantTraceID)
+			}
+			if gotSpanID != wantSpanID || gotSpanID == "" {
+				t.Errorf("trailer x-span-id = %q, want %q", gotSpanID, wantSpanID)
+			}
+		})
+	}
+}
+
+func TestMetricsUnaryInterceptorWithRegistry(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	// 两个独立的 registry 不应触发重复注册，且指标互不影响
+	reg1 := prometheus.NewRegistry()
+	reg2 := prometheus.NewRegistry()
+	interceptor1 := MetricsUnaryInterceptorWithRegistry(reg1)
+	interceptor2 := MetricsUnaryInterceptorWithRegistry(reg2)
+
+	for i := 0; i < 2; i++ {
+		if _, err := interceptor1(context.Background(), nil, info, handler); err != nil {
+			t.Fatalf("interceptor1() returned unexpected error: %v", err)
+		}
+	}
+	if _, err := interceptor2(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor2() returned unexpected error: %v", err)
+	}
+
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{code="OK",method="/test.Service/TestMethod"} %d
+`
+	if err := testutil.GatherAndCompare(reg1, strings.NewReader(fmt.Sprintf(expected, 2)), "grpc_requests_total"); err != nil {
+		t.Errorf("reg1: %v", err)
+	}
+	if err := testutil.GatherAndCompare(reg2, strings.NewReader(fmt.Sprintf(expected, 1)), "grpc_requests_total"); err != nil {
+		t.Errorf("reg2: %v", err)
+	}
+}
+
+func TestTraceUnaryInterceptor_DeadlineAttribute(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	interceptor := TraceUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+
+	deadline, ok := spanAttributes(spans[0])["rpc.deadline_ms"]
+	if !ok {
+		t.Fatal("rpc.deadline_ms not set when context has a deadline")
+	}
+	if got := deadline.AsInt64(); got <= 0 || got > 5000 {
+		t.Errorf("rpc.deadline_ms = %d, want in (0, 5000]", got)
+	}
+
+	if _, ok := spanAttributes(spans[1])["rpc.deadline_ms"]; ok {
+		t.Error("rpc.deadline_ms set when context has no deadline")
+	}
+}
+
+func TestMetricsUnaryInterceptor_LatencyMode(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantHistogram int
+		wantSummary   int
+	}{
+		{
+			name:          "histogram by default",
+			wantHistogram: 1,
+			wantSummary:   0,
+		},
+		{
+			name:          "summary objectives add summary",
+			opts:          []Option{WithLatencySummary(map[float64]float64{0.5: 0.05, 0.99: 0.001})},
+			wantHistogram: 1,
+			wantSummary:   1,
+		},
+		{
+			name:          "summary only",
+			opts:          []Option{WithLatencyMode(LatencySummary)},
+			wantHistogram: 0,
+			wantSummary:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			interceptor := MetricsUnaryInterceptorWithRegistry(reg, tt.opts...)
+
+			if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+				t.Fatalf("interceptor() returned unexpected error: %v", err)
+			}
+
+			histograms, err := testutil.GatherAndCount(reg, "grpc_request_duration_seconds")
+			if err != nil {
+				t.Fatalf("GatherAndCount() error: %v", err)
+			}
+			summaries, err := testutil.GatherAndCount(reg, "grpc_request_latency_summary_seconds")
+			if err != nil {
+				t.Fatalf("GatherAndCount() error: %v", err)
+			}
+
+			if histograms != tt.wantHistogram {
+				t.Errorf("histogram series = %d, want %d", histograms, tt.wantHistogram)
+			}
+			if summaries != tt.wantSummary {
+				t.Errorf("summary series = %d, want %d", summaries, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestTraceUnaryInterceptor_RequestIDValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		requestID string
+		wantKept  bool
+	}{
+		{
+			name:      "valid request ID",
+			requestID: "req-123",
+			wantKept:  true,
+		},
+		{
+			name:      "newline injection",
+			requestID: "req-123\nfake log line",
+			wantKept:  false,
+		},
+		{
+			name:      "too long",
+			requestID: strings.Repeat("a", 129),
+			wantKept:  false,
+		},
+		{
+			name:      "custom max length",
+			opts:      []Option{WithMaxRequestIDLength(4)},
+			requestID: "req-123",
+			wantKept:  false,
+		},
+		{
+			name:      "pattern mismatch",
+			opts:      []Option{WithRequestIDPattern(regexp.MustCompile(`^[0-9a-f]+$`))},
+			requestID: "req-123",
+			wantKept:  false,
+		},
+		{
+			name:      "pattern match",
+			opts:      []Option{WithRequestIDPattern(regexp.MustCompile(`^[0-9a-f]+$`))},
+			requestID: "abc123",
+			wantKept:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := TraceUnaryInterceptor(tt.opts...)
+
+			var gotRequestID string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				gotRequestID = log.RequestIDFromContext(ctx)
+				return "response", nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", tt.requestID))
+			info := &grpc.UnaryServerInfo{
+				FullMethod: "/test.Service/TestMethod",
+			}
+
+			if _, err := interceptor(ctx, nil, info, handler); err != nil {
+				t.Fatalf("interceptor() returned unexpected error: %v", err)
+			}
+
+			if kept := gotRequestID == tt.requestID; kept != tt.wantKept {
+				t.Errorf("request ID kept = %v, want %v (got %q)", kept, tt.wantKept, gotRequestID)
+			}
+			if gotRequestID == "" {
+				t.Error("request ID is empty, want fallback to generated ID")
+			}
+		})
+	}
+}
+
+func TestStatusDetailsJSON(t *testing.T) {
+	withDetails, err := status.New(codes.InvalidArgument, "bad request").WithDetails(
+		&errdetails.ErrorInfo{Reason: "INVALID_ORDER", Domain: "orders"},
+	)
+	if err != nil {
+		t.Fatalf("WithDetails() error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		err          error
+		wantEmpty    bool
+		wantContains string
+	}{
+		{
+			name:      "plain error",
+			err:       errors.New("boom"),
+			wantEmpty: true,
+		},
+		{
+			name:      "status without details",
+			err:       status.Error(codes.NotFound, "not found"),
+			wantEmpty: true,
+		},
+		{
+			name:         "status with details",
+			err:          withDetails.Err(),
+			wantContains: "INVALID_ORDER",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statusDetailsJSON(tt.err)
+			if tt.wantEmpty {
+				if got != "" {
+					t.Errorf("statusDetailsJSON() = %q, want empty", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.wantContains) {
+				t.Errorf("statusDetailsJSON() = %q, want it to contain %q", got, tt.wantContains)
+			}
+			var decoded []map[string]interface{}
+			if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+				t.Errorf("statusDetailsJSON() returned invalid JSON: %v", err)
+			}
+		})
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithKnownMethods(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithKnownMethods("/test.Service/Known"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	for _, method := range []string{"/test.Service/Known", "/synthetic.A/X", "/synthetic.B/Y"} {
+		info := &grpc.UnaryServerInfo{
+			FullMethod: method,
+		}
+		if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+			t.Fatalf("interceptor() returned unexpected error: %v", err)
+		}
+	}
+
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{code="OK",method="/test.Service/Known"} 1
+grpc_requests_total{code="OK",method="unknown"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithCallerLabel(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithCallerLabel("x-caller-service"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-caller-service", "billing"))
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{caller="billing",code="OK",method="/test.Service/TestMethod"} 1
+grpc_requests_total{caller="unknown",code="OK",method="/test.Service/TestMethod"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithOutcomeLabel(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithOutcomeLabel(true))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	for _, err := range []error{
+		nil,
+		status.Error(codes.Canceled, "client hung up"),
+		status.Error(codes.DeadlineExceeded, "too slow"),
+		status.Error(codes.NotFound, "missing"),
+		status.Error(codes.Internal, "boom"),
+	} {
+		err := err
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, err
+		}
+		_, _ = interceptor(context.Background(), nil, info, handler)
+	}
+
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{code="Canceled",method="/test.Service/TestMethod",outcome="cancelled"} 1
+grpc_requests_total{code="DeadlineExceeded",method="/test.Service/TestMethod",outcome="timeout"} 1
+grpc_requests_total{code="Internal",method="/test.Service/TestMethod",outcome="server_error"} 1
+grpc_requests_total{code="NotFound",method="/test.Service/TestMethod",outcome="client_error"} 1
+grpc_requests_total{code="OK",method="/test.Service/TestMethod",outcome="success"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+// captureLogs 将全局 logger 重定向到临时文件（JSON 格式），返回读取已写入日志的函数，测试结束后恢复默认 logger
+func captureLogs(t *testing.T) func() []map[string]interface{} {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	log.Init(
+		log.WithFilename(filename),
+		log.WithOutputPaths(nil),
+		log.WithFormat("json"),
+		log.WithLevel("debug"),
+	)
+	t.Cleanup(func() {
+		log.Init()
+	})
+
+	return func() []map[string]interface{} {
+		t.Helper()
+
+		data, err := os.ReadFile(filename)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatalf("read log file: %v", err)
+		}
+
+		var entries []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("unmarshal log line %q: %v", line, err)
+			}
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+}
+
+// findLog 返回第一条消息为 msg 的日志
+func findLog(entries []map[string]interface{}, msg string) map[string]interface{} {
+	for _, entry := range entries {
+		if entry["msg"] == msg {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestTraceUnaryInterceptor_HandlerLogFields(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		// 处理器派生的 context 中追加字段，同样应出现在完成日志中
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		AddLogFields(ctx, zap.String("order_id", "order-42"))
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	entry := findLog(readLogs(), "gRPC request completed")
+	if entry == nil {
+		t.Fatal("completion log not found")
+	}
+	if entry["order_id"] != "order-42" {
+		t.Errorf("completion log order_id = %v, want %q", entry["order_id"], "order-42")
+	}
+}
+
+// setNowFunc 在测试期间替换拦截器使用的时钟，测试结束后恢复
+func setNowFunc(t *testing.T, fn func() time.Time) {
+	t.Helper()
+
+	prev := nowFunc
+	nowFunc = fn
+	t.Cleanup(func() {
+		nowFunc = prev
+	})
+}
+
+// fakeClock 每次调用前进固定步长的时钟
+type fakeClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func TestMetricsUnaryInterceptor_DeterministicDuration(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0), step: 250 * time.Millisecond}
+	setNowFunc(t, clock.Now)
+
+	var observed time.Duration
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithObserver(
+		func(ctx context.Context, fullMethod string, duration time.Duration, err error) {
+			observed = duration
+		},
+	))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	if observed != 250*time.Millisecond {
+		t.Errorf("observed duration = %v, want %v", observed, 250*time.Millisecond)
+	}
+
+	expected := `
+# HELP grpc_request_duration_seconds gRPC request duration in seconds
+# TYPE grpc_request_duration_seconds histogram
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="0.005"} 0
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="0.01"} 0
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="0.025"} 0
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="0.05"} 0
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="0.1"} 0
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="0.25"} 1
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="0.5"} 1
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="1"} 1
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="2.5"} 1
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="5"} 1
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="10"} 1
+grpc_request_duration_seconds_bucket{code="OK",method="/test.Service/TestMethod",le="+Inf"} 1
+grpc_request_duration_seconds_sum{code="OK",method="/test.Service/TestMethod"} 0.25
+grpc_request_duration_seconds_count{code="OK",method="/test.Service/TestMethod"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_request_duration_seconds"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTraceUnaryInterceptor_WithLogSampleRate(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	okHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	errHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	}
+	newCtx := func(traceID string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-trace-id", traceID))
+	}
+
+	t.Run("rate zero drops happy path but keeps errors", func(t *testing.T) {
+		readLogs := captureLogs(t)
+		interceptor := TraceUnaryInterceptor(WithLogSampleRate(0))
+
+		_, _ = interceptor(newCtx("trace-ok"), nil, info, okHandler)
+		_, _ = interceptor(newCtx("trace-err"), nil, info, errHandler)
+
+		entries := readLogs()
+		if findLog(entries, "gRPC request started") != nil {
+			t.Error("started log should be sampled out")
+		}
+		if findLog(entries, "gRPC request completed") != nil {
+			t.Error("completed log should be sampled out")
+		}
+		if findLog(entries, "gRPC request failed") == nil {
+			t.Error("failed log should always be written")
+		}
+	})
+
+	t.Run("decision is deterministic per trace", func(t *testing.T) {
+		o := newOptions(WithLogSampleRate(0.5))
+
+		var kept int
+		for i := 0; i < 1000; i++ {
+			traceID := fmt.Sprintf("trace-%d", i)
+			sampled := o.logSampled(traceID)
+			if sampled != o.logSampled(traceID) {
+				t.Fatalf("logSampled(%q) is not deterministic", traceID)
+			}
+			if sampled {
+				kept++
+			}
+		}
+		if kept < 400 || kept > 600 {
+			t.Errorf("logSampled() kept %d of 1000 traces, want about 500", kept)
+		}
+	})
+}
+
+func TestInterceptors_WithDefaultErrorCode(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	tests := []struct {
+		name        string
+		handlerErr  error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			name:        "plain error is converted",
+			handlerErr:  errors.New("order not found"),
+			wantCode:    codes.Internal,
+			wantMessage: "order not found",
+		},
+		{
+			name:        "status error passes through",
+			handlerErr:  status.Error(codes.NotFound, "order not found"),
+			wantCode:    codes.NotFound,
+			wantMessage: "order not found",
+		},
+	}
+
+	interceptors := map[string]grpc.UnaryServerInterceptor{
+		"trace":   TraceUnaryInterceptor(WithDefaultErrorCode(codes.Internal)),
+		"metrics": MetricsUnaryInterceptorWithRegistry(prometheus.NewRegistry(), WithDefaultErrorCode(codes.Internal)),
+	}
+
+	for name, interceptor := range interceptors {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return nil, tt.handlerErr
+				}
+
+				_, err := interceptor(context.Background(), nil, info, handler)
+
+				st := status.Convert(err)
+				if st.Code() != tt.wantCode {
+					t.Errorf("interceptor() code = %v, want %v", st.Code(), tt.wantCode)
+				}
+				if st.Message() != tt.wantMessage {
+					t.Errorf("interceptor() message = %q, want %q", st.Message(), tt.wantMessage)
+				}
+			})
+		}
+	}
+}
+
+func TestTraceUnaryInterceptor_TraceFlagsLogField(t *testing.T) {
+	setupTestTracer(t)
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	entries := readLogs()
+	for _, msg := range []string{"gRPC request started", "gRPC request completed"} {
+		entry := findLog(entries, msg)
+		if entry == nil {
+			t.Fatalf("%q log not found", msg)
+		}
+		if entry["trace_flags"] != "01" {
+			t.Errorf("%q trace_flags = %v, want %q", msg, entry["trace_flags"], "01")
+		}
+	}
+}
+
+func TestTraceUnaryInterceptor_WithRequestIDFromSpan(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	t.Run("uses span id", func(t *testing.T) {
+		setupTestTracer(t)
+
+		var requestID, spanID string
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			requestID = log.RequestIDFromContext(ctx)
+			spanID = oteltrace.SpanContextFromContext(ctx).SpanID().String()
+			return "response", nil
+		}
+
+		interceptor := TraceUnaryInterceptor(WithRequestIDFromSpan(true))
+		if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+			t.Fatalf("interceptor() returned unexpected error: %v", err)
+		}
+
+		if requestID != spanID {
+			t.Errorf("requestID = %q, want span id %q", requestID, spanID)
+		}
+	})
+
+	t.Run("falls back to random without span", func(t *testing.T) {
+		var requestID string
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			requestID = log.RequestIDFromContext(ctx)
+			return "response", nil
+		}
+
+		interceptor := TraceUnaryInterceptor(WithRequestIDFromSpan(true))
+		if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+			t.Fatalf("interceptor() returned unexpected error: %v", err)
+		}
+
+		if len(requestID) != 32 {
+			t.Errorf("requestID = %q, want 32 hex characters", requestID)
+		}
+	})
+}
+
+func TestTraceInterceptors_WithTracerName(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	opts := []Option{WithTracerName("github.com/go-anyway/framework-interceptor", "v1.2.3")}
+	server := TraceUnaryInterceptor(opts...)
+	client := TraceUnaryClientInterceptor(opts...)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	if _, err := server(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("server interceptor returned unexpected error: %v", err)
+	}
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	if err := client(context.Background(), "/test.Service/TestMethod", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("client interceptor returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	for _, span := range spans {
+		scope := span.InstrumentationScope()
+		if scope.Name != "github.com/go-anyway/framework-interceptor" || scope.Version != "v1.2.3" {
+			t.Errorf("span %q scope = %s@%s, want github.com/go-anyway/framework-interceptor@v1.2.3", span.Name(), scope.Name, scope.Version)
+		}
+	}
+}
+
+func TestTraceInterceptors_WithSpanNameFormatter(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	const method = "/test.v1.OrderService/GetOrder"
+	formatter := func(fullMethod string) string {
+		return strings.TrimPrefix(fullMethod, "/test.v1.")
+	}
+	server := TraceUnaryInterceptor(WithSpanNameFormatter(formatter))
+	client := TraceUnaryClientInterceptor(WithSpanNameFormatter(formatter))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	if _, err := server(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler); err != nil {
+		t.Fatalf("server interceptor returned unexpected error: %v", err)
+	}
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	if err := client(context.Background(), method, nil, nil, nil, invoker); err != nil {
+		t.Fatalf("client interceptor returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	for _, span := range spans {
+		if span.Name() != "OrderService/GetOrder" {
+			t.Errorf("span name = %q, want %q", span.Name(), "OrderService/GetOrder")
+		}
+		if got := spanAttributes(span)[attribute.Key("rpc.method")]; got.AsString() != method {
+			t.Errorf("rpc.method = %q, want %q", got.AsString(), method)
+		}
+	}
+}
+
+func TestTraceUnaryClientInterceptor_WithClientLogging(t *testing.T) {
+	setupTestTracer(t)
+	readLogs := captureLogs(t)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return status.Error(codes.NotFound, "missing")
+	}
+
+	if err := TraceUnaryClientInterceptor()(context.Background(), "/test.Service/Quiet", nil, nil, nil, invoker); err == nil {
+		t.Fatal("interceptor() error = nil, want error")
+	}
+	if err := TraceUnaryClientInterceptor(WithClientLogging(true))(context.Background(), "/test.Service/Loud", nil, nil, nil, invoker); err == nil {
+		t.Fatal("interceptor() error = nil, want error")
+	}
+
+	entries := readLogs()
+	if len(entries) != 1 {
+		t.Fatalf("log entries = %d, want 1", len(entries))
+	}
+	entry := findLog(entries, "gRPC client call failed")
+	if entry == nil {
+		t.Fatal("client call log not found")
+	}
+	if entry["method"] != "/test.Service/Loud" {
+		t.Errorf("method = %v, want %q", entry["method"], "/test.Service/Loud")
+	}
+	if traceID, _ := entry["trace_id"].(string); len(traceID) != 32 {
+		t.Errorf("trace_id = %v, want 32 hex characters", entry["trace_id"])
+	}
+	if entry["status_code"] != codes.NotFound.String() {
+		t.Errorf("status_code = %v, want %q", entry["status_code"], codes.NotFound.String())
+	}
+}
+
+func TestTraceUnaryInterceptor_CustomLogMessages(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor(
+		WithStartMessage("rpc.start"),
+		WithCompleteMessage("rpc.done"),
+		WithFailMessage("rpc.fail"),
+	)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	})
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	})
+
+	entries := readLogs()
+	for _, msg := range []string{"rpc.start", "rpc.done", "rpc.fail"} {
+		if findLog(entries, msg) == nil {
+			t.Errorf("log %q not found", msg)
+		}
+	}
+	for _, msg := range []string{"gRPC request started", "gRPC request completed", "gRPC request failed"} {
+		if findLog(entries, msg) != nil {
+			t.Errorf("default log %q should be replaced", msg)
+		}
+	}
+}
+
+func TestTraceUnaryClientInterceptor_CompressionAttribute(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	client := TraceUnaryClientInterceptor()
+
+	if err := client(context.Background(), "/test.Service/TestMethod", nil, nil, nil, invoker, grpc.UseCompressor("gzip")); err != nil {
+		t.Fatalf("client interceptor returned unexpected error: %v", err)
+	}
+	if err := client(context.Background(), "/test.Service/TestMethod", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("client interceptor returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	for i, span := range spans {
+		got, ok := spanAttributes(span)[attribute.Key("rpc.grpc.request_compression")]
+		wantCompressed := i%2 == 0
+		if ok != wantCompressed {
+			t.Errorf("span %d has compression attribute = %v, want %v", i, ok, wantCompressed)
+		}
+		if wantCompressed && got.AsString() != "gzip" {
+			t.Errorf("span %d rpc.grpc.request_compression = %q, want %q", i, got.AsString(), "gzip")
+		}
+	}
+}
+
+func TestInjectedTraceContext(t *testing.T) {
+	setupTestTracer(t)
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(prev)
+	})
+
+	if traceID, spanID, sampled := InjectedTraceContext(context.Background()); traceID != "" || spanID != "" || sampled {
+		t.Errorf("InjectedTraceContext() without span = (%q, %q, %v), want empty", traceID, spanID, sampled)
+	}
+
+	var helperTraceID, helperSpanID string
+	var helperSampled bool
+	var injected metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		helperTraceID, helperSpanID, helperSampled = InjectedTraceContext(ctx)
+		injected, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := TraceUnaryClientInterceptor()(context.Background(), "/test.Service/TestMethod", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	want := fmt.Sprintf("00-%s-%s-01", helperTraceID, helperSpanID)
+	if got := injected.Get("traceparent"); len(got) != 1 || got[0] != want {
+		t.Errorf("injected traceparent = %v, want %q", got, want)
+	}
+	if !helperSampled {
+		t.Error("InjectedTraceContext() sampled = false, want true")
+	}
+}
+
+func TestTraceUnaryInterceptor_WithForceNewRoot(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	// context 中已有一个本地 span，模拟上游追踪 header 被剥离的情况
+	parentCtx, parent := otel.Tracer("test").Start(context.Background(), "local-parent")
+	defer parent.End()
+
+	if _, err := TraceUnaryInterceptor()(parentCtx, nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+	if _, err := TraceUnaryInterceptor(WithForceNewRoot(true))(parentCtx, nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	if spans[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("default span parent = %s, want %s", spans[0].Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+	if spans[1].Parent().IsValid() {
+		t.Errorf("forced root span parent = %s, want none", spans[1].Parent().SpanID())
+	}
+	if spans[1].SpanContext().TraceID() == parent.SpanContext().TraceID() {
+		t.Error("forced root span should start a new trace")
+	}
+}
+
+func TestTraceUnaryInterceptor_WithAccessLog(t *testing.T) {
+	readLogs := captureLogs(t)
+	clock := &fakeClock{now: time.Unix(0, 0), step: 1500 * time.Microsecond}
+	setNowFunc(t, clock.Now)
+
+	interceptor := TraceUnaryInterceptor(WithAccessLog(true), WithLogSampleRate(0))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-42"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+	_, _ = interceptor(ctx, nil, info, handler)
+
+	var access []map[string]interface{}
+	for _, entry := range readLogs() {
+		if entry["msg"] == "gRPC access" {
+			access = append(access, entry)
+		}
+	}
+	if len(access) != 1 {
+		t.Fatalf("access log lines = %d, want 1", len(access))
+	}
+
+	entry := access[0]
+	want := map[string]interface{}{
+		"method":      "/test.Service/TestMethod",
+		"code":        "NotFound",
+		"duration_ms": 1.5,
+		"request_id":  "req-42",
+		"peer":        "10.0.0.1:5000",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("access log %s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["trace_id"]; !ok {
+		t.Error("access log trace_id field missing")
+	}
+}
+
+func TestTraceUnaryInterceptor_WithHandlerBoundaryEvents(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	if _, err := TraceUnaryInterceptor()(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+	if _, err := TraceUnaryInterceptor(WithHandlerBoundaryEvents(true))(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	if got := len(spans[0].Events()); got != 0 {
+		t.Errorf("default span events = %d, want 0", got)
+	}
+
+	events := spans[1].Events()
+	if len(events) != 2 || events[0].Name != "handler.start" || events[1].Name != "handler.end" {
+		t.Fatalf("span events = %v, want [handler.start handler.end]", events)
+	}
+	if events[1].Time.Before(events[0].Time) {
+		t.Error("handler.end recorded before handler.start")
+	}
+}
+
+func TestTraceUnaryInterceptor_WithCodeLevelMapping(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor(WithCodeLevelMapping(map[codes.Code]zapcore.Level{
+		codes.NotFound: zapcore.InfoLevel,
+	}))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	for _, c := range []codes.Code{codes.NotFound, codes.Internal} {
+		c := c
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(c, "failed")
+		})
+	}
+
+	levels := make(map[string]interface{})
+	for _, entry := range readLogs() {
+		if entry["msg"] != "gRPC request failed" {
+			continue
+		}
+		errMsg, _ := entry["error"].(string)
+		levels[errMsg] = entry["level"]
+	}
+
+	want := map[string]string{
+		"rpc error: code = NotFound desc = failed": "INFO",
+		"rpc error: code = Internal desc = failed": "ERROR",
+	}
+	for errMsg, level := range want {
+		if levels[errMsg] != level {
+			t.Errorf("level for %q = %v, want %s", errMsg, levels[errMsg], level)
+		}
+	}
+}
+
+func TestTraceInterceptors_CausationID(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	md := metadata.Pairs("x-request-id", "req-2", "x-causation-id", "req-1")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if got := CausationIDFromContext(ctx); got != "req-1" {
+			t.Errorf("CausationIDFromContext() = %q, want %q", got, "req-1")
+		}
+		return nil, TraceUnaryClientInterceptor()(ctx, "/test.Downstream/Call", nil, nil, nil, invoker)
+	})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	if got := outgoing.Get("x-causation-id"); len(got) != 1 || got[0] != "req-2" {
+		t.Errorf("outgoing x-causation-id = %v, want [req-2]", got)
+	}
+	for _, msg := range []string{"gRPC request started", "gRPC request completed"} {
+		entry := findLog(readLogs(), msg)
+		if entry == nil {
+			t.Fatalf("%q log not found", msg)
+		}
+		if entry["causation_id"] != "req-1" {
+			t.Errorf("%q causation_id = %v, want %q", msg, entry["causation_id"], "req-1")
+		}
+	}
+	if got := CausationIDFromContext(context.Background()); got != "" {
+		t.Errorf("CausationIDFromContext(empty) = %q, want empty", got)
+	}
+}
+
+func TestTraceUnaryInterceptor_WithNoPropagationMethods(t *testing.T) {
+	recorder := setupTestTracer(t)
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(prev)
+	})
+
+	const remoteTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	md := metadata.Pairs(
+		"traceparent", "00-"+remoteTraceID+"-00f067aa0ba902b7-01",
+		"x-trace-id", "spoofed",
+	)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	var logTraceIDs []string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		logTraceIDs = append(logTraceIDs, log.TraceIDFromContext(ctx))
+		return nil, nil
+	}
+	interceptor := TraceUnaryInterceptor(WithNoPropagationMethods("/test.Admin/Reset"))
+
+	for _, method := range []string{"/test.Service/Public", "/test.Admin/Reset"} {
+		if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler); err != nil {
+			t.Fatalf("interceptor(%s) error = %v", method, err)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != remoteTraceID {
+		t.Errorf("propagated span trace ID = %s, want %s", got, remoteTraceID)
+	}
+	if spans[1].Parent().IsValid() || spans[1].SpanContext().TraceID().String() == remoteTraceID {
+		t.Error("no-propagation span should start a new root trace")
+	}
+	if logTraceIDs[1] != spans[1].SpanContext().TraceID().String() {
+		t.Errorf("no-propagation log trace ID = %q, want span trace ID %s", logTraceIDs[1], spans[1].SpanContext().TraceID())
+	}
+}
+
+func TestTraceUnaryClientInterceptor_LinkSpans(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	tracer := otel.Tracer("test")
+	_, batchA := tracer.Start(context.Background(), "batch-a")
+	batchA.End()
+	_, batchB := tracer.Start(context.Background(), "batch-b")
+	batchB.End()
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	interceptor := TraceUnaryClientInterceptor()
+
+	ctx := LinkSpans(context.Background(), oteltrace.Link{SpanContext: batchA.SpanContext()})
+	ctx = LinkSpans(ctx, oteltrace.Link{SpanContext: batchB.SpanContext()})
+	if err := interceptor(ctx, "/test.Service/Linked", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if err := interceptor(context.Background(), "/test.Service/Plain", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("ended spans = %d, want 4", len(spans))
+	}
+	links := spans[2].Links()
+	if len(links) != 2 {
+		t.Fatalf("links = %d, want 2", len(links))
+	}
+	if links[0].SpanContext.SpanID() != batchA.SpanContext().SpanID() ||
+		links[1].SpanContext.SpanID() != batchB.SpanContext().SpanID() {
+		t.Errorf("links = %v, want batch-a and batch-b", links)
+	}
+	if got := len(spans[3].Links()); got != 0 {
+		t.Errorf("links without LinkSpans = %d, want 0", got)
+	}
+}
+
+func TestMetricsUnaryInterceptor_TraceExemplar(t *testing.T) {
+	setupTestTracer(t)
+
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Exemplar",
+	}
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+	_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var traceIDs []string
+	for _, mf := range families {
+		if mf.GetName() != "grpc_request_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, b := range m.GetHistogram().GetBucket() {
+				for _, lp := range b.GetExemplar().GetLabel() {
+					if lp.GetName() == "trace_id" {
+						traceIDs = append(traceIDs, lp.GetValue())
+					}
+				}
+			}
+		}
+	}
+
+	want := span.SpanContext().TraceID().String()
+	if len(traceIDs) != 1 || traceIDs[0] != want {
+		t.Errorf("exemplar trace IDs = %v, want [%s]", traceIDs, want)
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithConstLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithConstLabels(map[string]string{
+		"version": "v1.2.3",
+		"region":  "us-east-1",
+	}))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{code="OK",method="/test.Service/TestMethod",region="us-east-1",version="v1.2.3"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTraceUnaryClientInterceptor_WithPropagationWarnings(t *testing.T) {
+	setupTestTracer(t)
+	readLogs := captureLogs(t)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	interceptor := TraceUnaryClientInterceptor(WithPropagationWarnings(true))
+
+	parentCtx, parent := otel.Tracer("test").Start(context.Background(), "handler")
+	defer parent.End()
+	if err := interceptor(parentCtx, "/test.Service/Propagated", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if err := interceptor(context.Background(), "/test.Service/Detached", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if err := TraceUnaryClientInterceptor()(context.Background(), "/test.Service/Quiet", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	var warned []interface{}
+	for _, entry := range readLogs() {
+		if entry["msg"] == "Outgoing gRPC call has no parent span, trace propagation may be broken" {
+			warned = append(warned, entry["method"])
+		}
+	}
+	if len(warned) != 1 || warned[0] != "/test.Service/Detached" {
+		t.Errorf("warned methods = %v, want [/test.Service/Detached]", warned)
+	}
+}
+
+func TestTraceInterceptors_WithNumericStatusAttribute(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	_, _ = TraceUnaryInterceptor()(context.Background(), nil, info, handler)
+	_, _ = TraceUnaryInterceptor(WithNumericStatusAttribute(true))(context.Background(), nil, info, handler)
+	_ = TraceUnaryClientInterceptor(WithNumericStatusAttribute(true))(context.Background(), "/test.Service/Call", nil, nil, nil, invoker)
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("ended spans = %d, want 3", len(spans))
+	}
+	if _, ok := spanAttributes(spans[0])["rpc.grpc.status_code"]; ok {
+		t.Error("rpc.grpc.status_code set without WithNumericStatusAttribute")
+	}
+	for i, want := range map[int]codes.Code{1: codes.NotFound, 2: codes.Unavailable} {
+		attrs := spanAttributes(spans[i])
+		if got := attrs["rpc.grpc.status_code"].AsInt64(); got != int64(want) {
+			t.Errorf("span %d rpc.grpc.status_code = %d, want %d", i, got, want)
+		}
+		if got := attrs["rpc.status_code"].AsString(); got != want.String() {
+			t.Errorf("span %d rpc.status_code = %q, want %q", i, got, want.String())
+		}
+	}
+}
+
+func TestLiteMetricsUnaryInterceptor(t *testing.T) {
+	okCounter := GRPCLiteRequestTotal.WithLabelValues("ok")
+	errCounter := GRPCLiteRequestTotal.WithLabelValues("error")
+	okBefore := testutil.ToFloat64(okCounter)
+	errBefore := testutil.ToFloat64(errCounter)
+
+	interceptor := LiteMetricsUnaryInterceptor()
+	for i, err := range []error{nil, nil, status.Error(codes.Internal, "boom")} {
+		err := err
+		info := &grpc.UnaryServerInfo{
+			FullMethod: fmt.Sprintf("/test.Service/Method%d", i),
+		}
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, err
+		})
+	}
+
+	if got := testutil.ToFloat64(okCounter) - okBefore; got != 2 {
+		t.Errorf("ok delta = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(errCounter) - errBefore; got != 1 {
+		t.Errorf("error delta = %v, want 1", got)
+	}
+}
+
+func TestTraceUnaryInterceptor_GatewayPrefixedHeaders(t *testing.T) {
+	recorder := setupTestTracer(t)
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(prev)
+	})
+
+	const remoteTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	md := metadata.MD{
+		"grpcgateway-traceparent":  []string{"00-" + remoteTraceID + "-00f067aa0ba902b7-01"},
+		"grpcgateway-x-request-id": []string{"gateway-request"},
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	var requestID string
+	_, err := TraceUnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/TestMethod"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		requestID = log.RequestIDFromContext(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != remoteTraceID {
+		t.Errorf("trace ID = %s, want %s", got, remoteTraceID)
+	}
+	if requestID != "gateway-request" {
+		t.Errorf("request ID = %q, want %q", requestID, "gateway-request")
+	}
+}
+
+func TestTraceUnaryInterceptor_PrebuiltLogger(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	md := metadata.Pairs("x-request-id", "req-logger")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	_, err := TraceUnaryInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		AddLogFields(ctx, zap.String("order_id", "o-1"))
+		LoggerFromContext(ctx).Info("handler log")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	entry := findLog(readLogs(), "handler log")
+	if entry == nil {
+		t.Fatal("handler log not found")
+	}
+	want := map[string]string{
+		"method":    "/test.Service/TestMethod",
+		"requestID": "req-logger",
+		"order_id":  "o-1",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %q", k, entry[k], v)
+		}
+	}
+
+	if LoggerFromContext(context.Background()) == nil {
+		t.Error("LoggerFromContext() without interceptor returned nil")
+	}
+}
+
+func TestMetricsUnaryInterceptorWithRegistry_RegisterTwice(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("registering metrics twice panicked: %v", r)
+		}
+	}()
+	for i := 0; i < 2; i++ {
+		interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithLatencyMode(LatencyHistogramAndSummary))
+		_, _ = interceptor(context.Background(), nil, info, handler)
+	}
+
+	// 两个拦截器复用同一组指标
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{code="OK",method="/test.Service/TestMethod"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTraceUnaryInterceptor_DebugHeader(t *testing.T) {
+	recorder := setupTestTracer(t)
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor(WithLogSampleRate(0))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	md := metadata.Pairs("x-request-id", "req-1", "x-debug", "1")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if !DebugFromContext(ctx) {
+			t.Error("DebugFromContext() = false, want true")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	// 调试请求绕过日志采样
+	for _, msg := range []string{"gRPC request started", "gRPC request completed"} {
+		entry := findLog(readLogs(), msg)
+		if entry == nil {
+			t.Fatalf("%q log not found", msg)
+		}
+		if entry["debug"] != true {
+			t.Errorf("%q debug = %v, want true", msg, entry["debug"])
+		}
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])["debug"]; !got.AsBool() {
+		t.Errorf("span debug = %v, want true", got.Emit())
+	}
+
+	// x-debug 为 false 时按普通请求处理
+	md = metadata.Pairs("x-request-id", "req-2", "x-debug", "false")
+	ctx = metadata.NewIncomingContext(context.Background(), md)
+	_, err = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if DebugFromContext(ctx) {
+			t.Error("DebugFromContext() = true, want false")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	for _, entry := range readLogs() {
+		if entry["requestID"] == "req-2" {
+			t.Errorf("unexpected log for sampled-out request: %v", entry)
+		}
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithBaggageMetricLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithBaggageMetricLabels("priority", "app.tier"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	priority, err := baggage.NewMember("priority", "high")
+	if err != nil {
+		t.Fatalf("baggage.NewMember() error = %v", err)
+	}
+	bag, err := baggage.New(priority)
+	if err != nil {
+		t.Fatalf("baggage.New() error = %v", err)
+	}
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{app_tier="unknown",code="OK",method="/test.Service/TestMethod",priority="high"} 1
+grpc_requests_total{app_tier="unknown",code="OK",method="/test.Service/TestMethod",priority="unknown"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInterceptors_ReplayTraffic(t *testing.T) {
+	recorder := setupTestTracer(t)
+	reg := prometheus.NewRegistry()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	traceInterceptor := TraceUnaryInterceptor()
+	metricsInterceptor := MetricsUnaryInterceptorWithRegistry(reg, WithSkipReplayMetrics(true))
+	call := func(ctx context.Context) {
+		t.Helper()
+		_, err := traceInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return metricsInterceptor(ctx, req, info, handler)
+		})
+		if err != nil {
+			t.Fatalf("interceptor() error = %v", err)
+		}
+	}
+
+	call(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-replay", "true")))
+	call(context.Background())
+
+	// 回放流量仍然被追踪
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	if got := spanAttributes(spans[0])["replay"]; !got.AsBool() {
+		t.Errorf("replay span replay = %v, want true", got.Emit())
+	}
+	if _, ok := spanAttributes(spans[1])["replay"]; ok {
+		t.Error("normal span has replay attribute")
+	}
+
+	// 只有正常流量计入指标
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{code="OK",method="/test.Service/TestMethod"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMetricsUnaryInterceptor_ReplayCountedByDefault(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-replay", "1"))
+	if _, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	expected := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{code="OK",method="/test.Service/TestMethod"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTraceUnaryInterceptor_WithTraceIDInErrors(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	md := metadata.Pairs("x-trace-id", "trace-123", "x-request-id", "req-123")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	withDetails, err := status.New(codes.InvalidArgument, "bad request").WithDetails(
+		&errdetails.ErrorInfo{Reason: "INVALID_ORDER", Domain: "orders"},
+	)
+	if err != nil {
+		t.Fatalf("WithDetails() error: %v", err)
+	}
+
+	interceptor := TraceUnaryInterceptor(WithTraceIDInErrors(true))
+	_, err = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, withDetails.Err()
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument || st.Message() != "bad request" {
+		t.Errorf("status = %v %q, want InvalidArgument %q", st.Code(), st.Message(), "bad request")
+	}
+	details := st.Details()
+	if len(details) != 2 {
+		t.Fatalf("details = %d, want 2", len(details))
+	}
+	if info, ok := details[0].(*errdetails.ErrorInfo); !ok || info.GetReason() != "INVALID_ORDER" {
+		t.Errorf("details[0] = %v, want original ErrorInfo", details[0])
+	}
+	if got := TraceIDFromError(err); got != "trace-123" {
+		t.Errorf("TraceIDFromError() = %q, want %q", got, "trace-123")
+	}
+	if info, ok := details[1].(*errdetails.ErrorInfo); !ok || info.GetMetadata()["request_id"] != "req-123" {
+		t.Errorf("details[1] = %v, want request_id req-123", details[1])
+	}
+
+	// 成功的请求和未开启选项时不修改结果
+	if _, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}); err != nil {
+		t.Errorf("interceptor() error = %v, want nil", err)
+	}
+	_, err = TraceUnaryInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	})
+	if got := TraceIDFromError(err); got != "" {
+		t.Errorf("TraceIDFromError() without option = %q, want empty", got)
+	}
+}
+
+func TestTraceUnaryInterceptor_LogsCancelCause(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	md := metadata.Pairs("x-request-id", "req-1")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name      string
+		cancel    func(parent context.Context) context.Context
+		wantCause interface{}
+	}{
+		{
+			name: "cancel with cause",
+			cancel: func(parent context.Context) context.Context {
+				ctx, cancel := context.WithCancelCause(parent)
+				cancel(errors.New("upstream gateway closed connection"))
+				return ctx
+			},
+			wantCause: "upstream gateway closed connection",
+		},
+		{
+			name: "plain cancel",
+			cancel: func(parent context.Context) context.Context {
+				ctx, cancel := context.WithCancel(parent)
+				cancel()
+				return ctx
+			},
+			wantCause: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readLogs := captureLogs(t)
+			ctx := tt.cancel(metadata.NewIncomingContext(context.Background(), md))
+
+			_, err := TraceUnaryInterceptor()(ctx, nil, info, handler)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("interceptor() error = %v, want %v", err, context.Canceled)
+			}
+
+			entry := findLog(readLogs(), "gRPC request failed")
+			if entry == nil {
+				t.Fatal("failure log not found")
+			}
+			if entry["cancel_cause"] != tt.wantCause {
+				t.Errorf("cancel_cause = %v, want %v", entry["cancel_cause"], tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestTraceUnaryInterceptor_WithSyncOnError(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	var synced int
+	prev := syncLogger
+	syncLogger = func(logger *zap.Logger) error {
+		synced++
+		return logger.Sync()
+	}
+	t.Cleanup(func() {
+		syncLogger = prev
+	})
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	}
+	succeeding := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	// 默认不刷新
+	_, _ = TraceUnaryInterceptor()(ctx, nil, info, failing)
+	if synced != 0 {
+		t.Errorf("synced = %d without option, want 0", synced)
+	}
+
+	interceptor := TraceUnaryInterceptor(WithSyncOnError(true))
+	_, _ = interceptor(ctx, nil, info, succeeding)
+	if synced != 0 {
+		t.Errorf("synced = %d after success, want 0", synced)
+	}
+	_, _ = interceptor(ctx, nil, info, failing)
+	if synced != 1 {
+		t.Errorf("synced = %d after failure, want 1", synced)
+	}
+	if findLog(readLogs(), "gRPC request failed") == nil {
+		t.Error("failure log not found")
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithSplitMethodLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithSplitMethodLabels(true))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	for _, method := range []string{"/orders.v1.OrderService/Create", "/orders.v1.OrderService/Get", "malformed"} {
+		if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler); err != nil {
+			t.Fatalf("interceptor() returned unexpected error: %v", err)
+		}
+	}
+
+	expected := `
+# HELP grpc_service_requests_total Total number of gRPC requests
+# TYPE grpc_service_requests_total counter
+grpc_service_requests_total{code="OK",method="Create",service="orders.v1.OrderService"} 1
+grpc_service_requests_total{code="OK",method="Get",service="orders.v1.OrderService"} 1
+grpc_service_requests_total{code="OK",method="malformed",service="unknown"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grpc_service_requests_total", "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMetricsUnaryInterceptor_WithSplitMethodLabelsDefaultRegistry(t *testing.T) {
+	// 拆分后的指标与全局指标不同名，可以直接注册到默认 registry
+	interceptor := MetricsUnaryInterceptor(WithSplitMethodLabels(true))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/orders.v1.OrderService/DefaultRegistry",
+	}
+
+	global := metrics.GRPCRequestTotal.WithLabelValues(info.FullMethod, codes.OK.String())
+	before := testutil.ToFloat64(global)
+
+	if _, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var found bool
+	for _, mf := range families {
+		if mf.GetName() != "grpc_service_requests_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := make(map[string]string)
+			for _, lp := range m.GetLabel() {
+				labels[lp.GetName()] = lp.GetValue()
+			}
+			if labels["service"] == "orders.v1.OrderService" && labels["method"] == "DefaultRegistry" {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("grpc_service_requests_total has no series for orders.v1.OrderService/DefaultRegistry")
+	}
+	if got := testutil.ToFloat64(global) - before; got != 0 {
+		t.Errorf("grpc_requests_total delta = %v, want 0", got)
+	}
+}
+
+func TestSplitFullMethod(t *testing.T) {
+	tests := []struct {
+		fullMethod  string
+		wantService string
+		wantMethod  string
+	}{
+		{"/pkg.Service/Method", "pkg.Service", "Method"},
+		{"/Service/Method", "Service", "Method"},
+		{"pkg.Service/Method", "unknown", "pkg.Service/Method"},
+		{"/pkg.Service/", "unknown", "/pkg.Service/"},
+		{"//Method", "unknown", "//Method"},
+		{"/a/b/c", "unknown", "/a/b/c"},
+		{"", "unknown", ""},
+	}
+	for _, tt := range tests {
+		service, method := splitFullMethod(tt.fullMethod)
+		if service != tt.wantService || method != tt.wantMethod {
+			t.Errorf("splitFullMethod(%q) = (%q, %q), want (%q, %q)", tt.fullMethod, service, method, tt.wantService, tt.wantMethod)
+		}
+	}
+}
+
+// tracerOnlyProvider 只实现 TracerProvider 接口的自定义 provider，没有 ForceFlush 等 SDK 方法
+type tracerOnlyProvider struct {
+	oteltrace.TracerProvider
+}
+
+func TestTraceUnaryInterceptor_CustomTracerProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tracerOnlyProvider{tp})
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+		_ = tp.Shutdown(context.Background())
+	})
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	_, err := TraceUnaryInterceptor()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])["rpc.method"].AsString(); got != info.FullMethod {
+		t.Errorf("rpc.method = %q, want %q", got, info.FullMethod)
+	}
+}
+
+func TestMetricsUnaryInterceptor_DefaultLatencySummary(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/DefaultSummary",
+	}
+	interceptor := MetricsUnaryInterceptor(WithLatencyMode(LatencyHistogramAndSummary))
+	if _, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	summary, ok := GRPCRequestLatencySummary.WithLabelValues(info.FullMethod, codes.OK.String()).(prometheus.Metric)
+	if !ok {
+		t.Fatal("summary is not a prometheus.Metric")
+	}
+	var m dto.Metric
+	if err := summary.Write(&m); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := m.GetSummary().GetSampleCount(); got != 1 {
+		t.Errorf("summary sample count = %d, want 1", got)
+	}
+}
+
+func TestMetricsUnaryInterceptor_ConflictingSummaryObjectives(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	MetricsUnaryInterceptorWithRegistry(reg, WithLatencySummary(map[float64]float64{0.5: 0.05}))
+	// 相同的分位数目标复用已有的 Summary
+	MetricsUnaryInterceptorWithRegistry(reg, WithLatencySummary(map[float64]float64{0.5: 0.05}))
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"different objectives in the same registry", func() {
+			MetricsUnaryInterceptorWithRegistry(reg, WithLatencySummary(map[float64]float64{0.99: 0.001}))
+		}},
+		{"custom objectives in the default registry", func() {
+			MetricsUnaryInterceptor(WithLatencySummary(map[float64]float64{0.99: 0.001}))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic for conflicting summary objectives")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+// assertPanics 断言 fn 发生 panic
+func assertPanics(t *testing.T, name string, fn func()) {
+	t.Helper()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s did not panic", name)
+		}
+	}()
+	fn()
+}
+
+func TestMetricsUnaryInterceptor_LabelOptionsRequireRegistry(t *testing.T) {
+	assertPanics(t, "MetricsUnaryInterceptor(WithCallerLabel)", func() {
+		MetricsUnaryInterceptor(WithCallerLabel("x-caller-service"))
+	})
+	assertPanics(t, "MetricsUnaryInterceptor(WithConstLabels)", func() {
+		MetricsUnaryInterceptor(WithConstLabels(map[string]string{"version": "v1.2.3"}))
+	})
+	assertPanics(t, "MetricsUnaryInterceptor(WithBaggageMetricLabels)", func() {
+		MetricsUnaryInterceptor(WithBaggageMetricLabels("priority"))
+	})
+	assertPanics(t, "MetricsUnaryInterceptor(WithOutcomeLabel)", func() {
+		MetricsUnaryInterceptor(WithOutcomeLabel(true))
+	})
+	// 关闭时标签集合不变，仍使用全局指标
+	MetricsUnaryInterceptor(WithOutcomeLabel(false))
+}
+
+func TestRegisterCollector_InconsistentLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	MetricsUnaryInterceptorWithRegistry(reg)
+
+	// 同一 registry 中标签集合不一致的指标无法注册，直接 panic 而不是静默丢弃
+	assertPanics(t, "MetricsUnaryInterceptorWithRegistry(WithCallerLabel)", func() {
+		MetricsUnaryInterceptorWithRegistry(reg, WithCallerLabel("x-caller-service"))
+	})
+}
+
+func TestTraceUnaryInterceptor_NoopProviderReplaced(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	interceptor := TraceUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	// 第一次请求后空操作的 provider 被标记，之后的请求不再创建 span
+	for i := 0; i < 2; i++ {
+		if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+			t.Fatalf("interceptor() error = %v", err)
+		}
+	}
+	if !isNoopTracerProvider(otel.GetTracerProvider()) {
+		t.Fatal("noop TracerProvider was not marked")
+	}
+
+	// 替换为 SDK provider 后标记失效，span 正常记录
+	recorder := setupTestTracer(t)
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if got := len(recorder.Ended()); got != 1 {
+		t.Errorf("ended spans = %d, want 1", got)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// keepAttributeKey 标记 span 因失败或慢请求需要保留的属性，KeepSampler 据此强制采样
+	keepAttributeKey = attribute.Key("sampling.keep")
+	// keepReasonAttributeKey 保留 span 的原因：error 或 slow
+	keepReasonAttributeKey = attribute.Key("sampling.keep_reason")
+)
+
+// KeepSampler 包装 base 采样器，对带有 sampling.keep=true 属性的 span 总是采样，其余交给 base 决定
+// 配合 WithKeepErrors、WithKeepSlowerThan 使用：OTel 在 span 开始时就已做出采样决策，被头部采样丢弃的 span 无法在结束时补录，
+// 因此 trace 拦截器会为这些请求补建一个带有 sampling.keep 属性的根 span，需要 TracerProvider 使用 KeepSampler 才能保证其被导出
+func KeepSampler(base sdktrace.Sampler) sdktrace.Sampler {
+	return keepSampler{base: base}
+}
+
+// keepSampler KeepSampler 的实现
+type keepSampler struct {
+	base sdktrace.Sampler
+}
+
+func (s keepSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key == keepAttributeKey && attr.Value.AsBool() {
+			return sdktrace.SamplingResult{
+				Decision:   sdktrace.RecordAndSample,
+				Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s keepSampler) Description() string {
+	return "KeepSampler{" + s.base.Description() + "}"
+}
+
+// keepReason 返回请求需要保留 span 的原因，不需要保留时返回空字符串
+func (o *options) keepReason(err error, elapsed time.Duration) string {
+	switch {
+	case o.keepErrors && err != nil:
+		return "error"
+	case o.keepSlowerThan > 0 && elapsed >= o.keepSlowerThan:
+		return "slow"
+	}
+	return ""
+}
+
+// keepSpan 标记需要保留的 span。span 正在记录时直接添加 sampling.keep 属性，便于下游尾部采样保留；
+// span 已被头部采样丢弃时，补建一个从请求开始时间起算、链接到原 span 的根 span
+func (o *options) keepSpan(ctx context.Context, span oteltrace.Span, method, reason string, start time.Time, err error) {
+	keepAttrs := []attribute.KeyValue{
+		keepAttributeKey.Bool(true),
+		keepReasonAttributeKey.String(reason),
+	}
+	if span.IsRecording() {
+		span.SetAttributes(keepAttrs...)
+		return
+	}
+
+	attrs := append(keepAttrs,
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.status_code", status.Code(err).String()),
+	)
+	startOpts := []oteltrace.SpanStartOption{
+		oteltrace.WithNewRoot(),
+		oteltrace.WithTimestamp(start),
+		oteltrace.WithAttributes(attrs...),
+	}
+	if sc := span.SpanContext(); sc.IsValid() {
+		startOpts = append(startOpts, oteltrace.WithLinks(oteltrace.Link{SpanContext: sc}))
+	}
+	_, kept := o.startSpan(ctx, o.spanName(method), startOpts...)
+	if err != nil {
+		kept.RecordError(err)
+	}
+	kept.End()
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// setupSamplerTracer 使用指定采样器设置全局 TracerProvider，测试结束后恢复
+func setupSamplerTracer(t *testing.T, sampler sdktrace.Sampler) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder))
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestTraceUnaryInterceptor_KeepDroppedSpans(t *testing.T) {
+	recorder := setupSamplerTracer(t, KeepSampler(sdktrace.NeverSample()))
+
+	interceptor := TraceUnaryInterceptor(WithKeepErrors(true), WithKeepSlowerThan(time.Second))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	tests := []struct {
+		name       string
+		delay      time.Duration
+		err        error
+		wantReason string
+	}{
+		{name: "fast success", delay: 0},
+		{name: "error", err: status.Error(codes.Internal, "boom"), wantReason: "error"},
+		{name: "slow", delay: 2 * time.Second, wantReason: "slow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Unix(0, 0)}
+			setNowFunc(t, clock.Now)
+			before := len(recorder.Ended())
+
+			_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				clock.now = clock.now.Add(tt.delay)
+				return nil, tt.err
+			})
+
+			spans := recorder.Ended()[before:]
+			if tt.wantReason == "" {
+				if len(spans) != 0 {
+					t.Errorf("ended spans = %d, want 0", len(spans))
+				}
+				return
+			}
+			if len(spans) != 1 {
+				t.Fatalf("ended spans = %d, want 1", len(spans))
+			}
+			attrs := spanAttributes(spans[0])
+			if !attrs["sampling.keep"].AsBool() || attrs["sampling.keep_reason"].AsString() != tt.wantReason {
+				t.Errorf("keep attributes = %v/%v, want true/%s", attrs["sampling.keep"], attrs["sampling.keep_reason"], tt.wantReason)
+			}
+			if !spans[0].StartTime().Equal(time.Unix(0, 0)) {
+				t.Errorf("start time = %v, want request start", spans[0].StartTime())
+			}
+		})
+	}
+}
+
+func TestTraceUnaryInterceptor_KeepRecordingSpan(t *testing.T) {
+	recorder := setupSamplerTracer(t, KeepSampler(sdktrace.AlwaysSample()))
+
+	interceptor := TraceUnaryInterceptor(WithKeepErrors(true))
+	_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/TestMethod"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])["sampling.keep_reason"].AsString(); got != "error" {
+		t.Errorf("sampling.keep_reason = %q, want error", got)
+	}
+}
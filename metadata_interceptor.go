@@ -0,0 +1,145 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequireMetadataUnaryInterceptor 创建必需 metadata 校验拦截器
+// 所有请求都必须携带 keys 中的 metadata，缺失时返回 InvalidArgument 并列出缺失的 key
+func RequireMetadataUnaryInterceptor(keys ...string) grpc.UnaryServerInterceptor {
+	return RequireMetadataPerMethodUnaryInterceptor(keys, nil)
+}
+
+// RequireMetadataPerMethodUnaryInterceptor 创建支持按方法配置的必需 metadata 校验拦截器
+// global 对所有方法生效，perMethod 以完整方法名为 key 追加该方法额外需要的 metadata
+func RequireMetadataPerMethodUnaryInterceptor(global []string, perMethod map[string][]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := fullMethodFromInfo(info)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		missing := missingMetadataKeys(md, global, nil)
+		missing = missingMetadataKeys(md, perMethod[method], missing)
+		if len(missing) > 0 {
+			GRPCRequestRejectedTotal.WithLabelValues(method, "missing_metadata").Inc()
+			return nil, status.Errorf(codes.InvalidArgument, "missing required metadata: %s", strings.Join(missing, ", "))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// missingMetadataKeys 将 md 中缺失的 key 追加到 missing 并返回
+func missingMetadataKeys(md metadata.MD, keys []string, missing []string) []string {
+	for _, key := range keys {
+		if lookupMetadata(md, key) == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// SanitizeMetadataUnaryInterceptor 创建敏感 metadata 清理拦截器
+// 在调用处理器之前从 incoming metadata 中移除 keys（大小写不敏感，同时移除带有 metadataKeyPrefixes 前缀的同名 header），
+// 防止授权凭证等敏感信息被写入日志或转发给下游服务
+func SanitizeMetadataUnaryInterceptor(keys ...string) grpc.UnaryServerInterceptor {
+	strip := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		strip[strings.ToLower(key)] = struct{}{}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(strip) > 0 {
+			ctx = metadata.NewIncomingContext(ctx, sanitizeMetadata(md, strip))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// sanitizeMetadata 返回不包含 strip 中 key 的 metadata 副本
+func sanitizeMetadata(md metadata.MD, strip map[string]struct{}) metadata.MD {
+	sanitized := make(metadata.MD, len(md))
+	for k, values := range md {
+		if shouldStripMetadataKey(strings.ToLower(k), strip) {
+			continue
+		}
+		sanitized[k] = values
+	}
+	return sanitized
+}
+
+// shouldStripMetadataKey 判断小写的 key 本身或去掉代理前缀后是否在 strip 中
+func shouldStripMetadataKey(key string, strip map[string]struct{}) bool {
+	if _, ok := strip[key]; ok {
+		return true
+	}
+	for _, prefix := range metadataKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			if _, ok := strip[key[len(prefix):]]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ForwardMetadataClientInterceptor 创建 metadata 转发客户端拦截器
+// 将当前请求 incoming metadata 中的 keys（例如 x-tenant-id）复制到下游调用的 outgoing metadata，
+// 只复制存在且非空的 key；outgoing metadata 中已经显式设置的 key 不会被覆盖
+func ForwardMetadataClientInterceptor(keys ...string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		incoming, ok := metadata.FromIncomingContext(ctx)
+		if !ok || len(keys) == 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		outgoing, ok := metadata.FromOutgoingContext(ctx)
+		if !ok {
+			outgoing = metadata.MD{}
+		}
+
+		forwarded := false
+		for _, key := range keys {
+			if len(outgoing.Get(key)) > 0 {
+				continue
+			}
+			var values []string
+			for _, v := range incoming.Get(key) {
+				if v != "" {
+					values = append(values, v)
+				}
+			}
+			if len(values) == 0 {
+				continue
+			}
+			outgoing.Set(key, values...)
+			forwarded = true
+		}
+		if forwarded {
+			ctx = metadata.NewOutgoingContext(ctx, outgoing)
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequireMetadataPerMethodUnaryInterceptor(t *testing.T) {
+	interceptor := RequireMetadataPerMethodUnaryInterceptor(
+		[]string{"x-api-version"},
+		map[string][]string{
+			"/test.Service/Write": {"x-tenant-id"},
+		},
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		md          metadata.MD
+		wantCode    codes.Code
+		wantMissing []string
+	}{
+		{
+			name:     "global key present",
+			method:   "/test.Service/Read",
+			md:       metadata.Pairs("x-api-version", "2"),
+			wantCode: codes.OK,
+		},
+		{
+			name:        "global key missing",
+			method:      "/test.Service/Read",
+			md:          metadata.Pairs("x-other", "value"),
+			wantCode:    codes.InvalidArgument,
+			wantMissing: []string{"x-api-version"},
+		},
+		{
+			name:        "per-method key missing",
+			method:      "/test.Service/Write",
+			md:          metadata.Pairs("x-api-version", "2"),
+			wantCode:    codes.InvalidArgument,
+			wantMissing: []string{"x-tenant-id"},
+		},
+		{
+			name:        "global and per-method keys missing",
+			method:      "/test.Service/Write",
+			md:          metadata.MD{},
+			wantCode:    codes.InvalidArgument,
+			wantMissing: []string{"x-api-version", "x-tenant-id"},
+		},
+		{
+			name:     "all keys present",
+			method:   "/test.Service/Write",
+			md:       metadata.Pairs("x-api-version", "2", "x-tenant-id", "tenant-a"),
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			info := &grpc.UnaryServerInfo{
+				FullMethod: tt.method,
+			}
+
+			_, err := interceptor(ctx, nil, info, handler)
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			for _, key := range tt.wantMissing {
+				if !strings.Contains(status.Convert(err).Message(), key) {
+					t.Errorf("error message %q does not list missing key %q", status.Convert(err).Message(), key)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireMetadataUnaryInterceptor_NoMetadata(t *testing.T) {
+	interceptor := RequireMetadataUnaryInterceptor("x-api-version")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Read",
+	}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.InvalidArgument)
+	}
+}
+
+func TestSanitizeMetadataUnaryInterceptor(t *testing.T) {
+	interceptor := SanitizeMetadataUnaryInterceptor("Authorization", "x-api-key")
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Read",
+	}
+
+	md := metadata.MD{
+		"authorization":         []string{"Bearer secret"},
+		"grpcweb-authorization": []string{"Bearer secret"},
+		"x-api-key":             []string{"key"},
+		"x-request-id":          []string{"req-1"},
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	var got metadata.MD
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = metadata.FromIncomingContext(ctx)
+		return "response", nil
+	}
+
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"authorization", "grpcweb-authorization", "x-api-key"} {
+		if values := got.Get(key); len(values) > 0 {
+			t.Errorf("metadata %q = %v, want removed", key, values)
+		}
+	}
+	if values := got.Get("x-request-id"); len(values) != 1 || values[0] != "req-1" {
+		t.Errorf("metadata x-request-id = %v, want [req-1]", values)
+	}
+	if values := md.Get("authorization"); len(values) != 1 {
+		t.Errorf("original metadata was modified: authorization = %v", values)
+	}
+}
+
+func TestForwardMetadataClientInterceptor(t *testing.T) {
+	interceptor := ForwardMetadataClientInterceptor("x-tenant-id", "X-Locale", "x-missing", "x-empty", "x-caller")
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		"x-tenant-id":   []string{"acme"},
+		"x-locale":      []string{"zh-CN"},
+		"x-empty":       []string{""},
+		"x-caller":      []string{"gateway"},
+		"authorization": []string{"Bearer secret"},
+	})
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-caller", "orders")
+
+	var got metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		got, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	if err := interceptor(ctx, "/test.Service/Read", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	want := map[string][]string{
+		"x-tenant-id": {"acme"},
+		"x-locale":    {"zh-CN"},
+		"x-caller":    {"orders"},
+	}
+	for key, values := range want {
+		if v := got.Get(key); len(v) != len(values) || v[0] != values[0] {
+			t.Errorf("outgoing %q = %v, want %v", key, v, values)
+		}
+	}
+	for _, key := range []string{"x-missing", "x-empty", "authorization"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("outgoing %q = %v, want absent", key, got[key])
+		}
+	}
+}
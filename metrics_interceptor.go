@@ -18,33 +18,345 @@ package interceptor
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-anyway/framework-metrics"
 
+	"github.com/prometheus/client_golang/prometheus"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// defaultSummaryObjectives 未指定分位数目标时 Summary 使用的默认值
+var defaultSummaryObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+
+// metricLabel 在 method、code 之外附加的指标标签
+type metricLabel struct {
+	name  string
+	value func(ctx context.Context, code codes.Code) string
+}
+
+// grpcMetrics metrics 拦截器使用的指标集合
+type grpcMetrics struct {
+	requestTotal    *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	// requestSummary 仅在启用 Summary 时创建
+	requestSummary *prometheus.SummaryVec
+	// observeHistogram 是否记录耗时直方图
+	observeHistogram bool
+	// extraLabels 附加的标签，顺序与指标的标签名一致
+	extraLabels []metricLabel
+	// splitMethod 是否将完整方法名拆分为 service、method 两个标签
+	splitMethod bool
+}
+
+// buildGRPCMetrics 根据配置创建指标集合
+// reg 为 nil 且没有附加标签、常量标签、也未拆分方法名时使用 framework-metrics 的全局指标，其余新建的指标注册到 reg（为 nil 时为默认 registry）。
+// 附加标签（WithCallerLabel、WithOutcomeLabel、WithBaggageMetricLabels 等）和常量标签（WithConstLabels）会改变指标的标签集合，
+// 而默认 registry 中已注册了 framework-metrics 的同名指标，注册必然失败，因此 reg 为 nil 时使用这些选项会直接 panic，
+// 需要通过 MetricsUnaryInterceptorWithRegistry 或 WithMetricsRegistry 指定独立的 registry，避免指标被静默丢弃。
+// 拆分方法名（WithSplitMethodLabels）时 method 标签只含方法名，与全局指标的含义不同，因此改用 grpc_service_ 前缀的指标名，
+// 可以与全局指标共存于默认 registry
+func buildGRPCMetrics(reg prometheus.Registerer, o *options) *grpcMetrics {
+	extraLabels := o.metricLabels()
+	if reg == nil && (len(extraLabels) > 0 || len(o.constLabels) > 0) {
+		panic("interceptor: metric label options change the label set of grpc_requests_total and grpc_request_duration_seconds, " +
+			"which conflicts with the framework-metrics collectors in the default registry; use WithMetricsRegistry or MetricsUnaryInterceptorWithRegistry")
+	}
+	namePrefix := "grpc_"
+	labelNames := []string{"method", "code"}
+	if o.splitMethodLabels {
+		namePrefix = "grpc_service_"
+		labelNames = []string{"service", "method", "code"}
+	}
+	for _, l := range extraLabels {
+		labelNames = append(labelNames, l.name)
+	}
+
+	useGlobal := reg == nil && len(extraLabels) == 0 && len(o.constLabels) == 0 && !o.splitMethodLabels
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	var m *grpcMetrics
+	if useGlobal {
+		m = defaultGRPCMetrics()
+	} else {
+		m = newGRPCMetrics(reg, namePrefix, labelNames, o.constLabels)
+	}
+	m.extraLabels = extraLabels
+	m.splitMethod = o.splitMethodLabels
+
+	mode := o.resolvedLatencyMode()
+	m.observeHistogram = mode != LatencySummary
+	if mode != LatencyHistogram {
+		objectives := o.summaryObjectives
+		if objectives == nil {
+			objectives = defaultSummaryObjectives
+		}
+		if useGlobal {
+			if !maps.Equal(objectives, defaultSummaryObjectives) {
+				panic("interceptor: custom latency summary objectives require MetricsUnaryInterceptorWithRegistry, " +
+					"GRPCRequestLatencySummary in the default registry uses the default objectives")
+			}
+			m.requestSummary = GRPCRequestLatencySummary
+		} else {
+			m.requestSummary = newLatencySummary(reg, namePrefix, labelNames, o.constLabels, objectives)
+		}
+	}
+
+	return m
+}
+
+var (
+	// summaryObjectivesMu 保护 summaryObjectives
+	summaryObjectivesMu sync.Mutex
+	// summaryObjectives 各 registry 中耗时 Summary 注册时使用的分位数目标，用于检测冲突
+	summaryObjectives = make(map[summaryKey]map[float64]float64)
+)
+
+// summaryKey 标识注册到某个 registry 的耗时 Summary
+type summaryKey struct {
+	reg  prometheus.Registerer
+	name string
+}
+
+// newLatencySummary 创建名为 <namePrefix>request_latency_summary_seconds 的耗时 Summary 并注册到 reg
+// reg 中已有 Summary 时复用；Summary 的分位数目标在注册后无法修改，已有 Summary 的目标与 objectives 不同时 panic，
+// 避免后创建的拦截器配置被静默忽略
+func newLatencySummary(reg prometheus.Registerer, namePrefix string, labelNames []string, constLabels prometheus.Labels, objectives map[float64]float64) *prometheus.SummaryVec {
+	summaryObjectivesMu.Lock()
+	defer summaryObjectivesMu.Unlock()
+
+	key := summaryKey{reg: reg, name: namePrefix + "request_latency_summary_seconds"}
+	if existing, ok := summaryObjectives[key]; ok && !maps.Equal(existing, objectives) {
+		panic(fmt.Sprintf("interceptor: latency summary already registered with objectives %v, got %v", existing, objectives))
+	}
+
+	summary := prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:        key.name,
+			Help:        "gRPC request latency summary in seconds",
+			Objectives:  objectives,
+			ConstLabels: constLabels,
+		},
+		labelNames,
+	)
+	summary = registerCollector(reg, summary)
+	summaryObjectives[key] = maps.Clone(objectives)
+	return summary
+}
+
+// defaultGRPCMetrics 返回 framework-metrics 中注册到默认 registry 的全局指标
+func defaultGRPCMetrics() *grpcMetrics {
+	return &grpcMetrics{
+		requestTotal:    metrics.GRPCRequestTotal,
+		requestDuration: metrics.GRPCRequestDuration,
+	}
+}
+
+// newGRPCMetrics 创建以 namePrefix 为前缀的一组指标（namePrefix 为 grpc_ 时与全局指标同名），并注册到指定的 registry，
+// constLabels 附加到每个指标
+func newGRPCMetrics(reg prometheus.Registerer, namePrefix string, labelNames []string, constLabels prometheus.Labels) *grpcMetrics {
+	m := &grpcMetrics{
+		requestTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        namePrefix + "requests_total",
+				Help:        "Total number of gRPC requests",
+				ConstLabels: constLabels,
+			},
+			labelNames,
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        namePrefix + "request_duration_seconds",
+				Help:        "gRPC request duration in seconds",
+				Buckets:     prometheus.DefBuckets,
+				ConstLabels: constLabels,
+			},
+			labelNames,
+		),
+	}
+	m.requestTotal = registerCollector(reg, m.requestTotal)
+	m.requestDuration = registerCollector(reg, m.requestDuration)
+	return m
+}
+
+// registerCollector 将 c 注册到 reg，同名指标已注册时复用已有的 collector，避免重复创建拦截器时 panic
+// 其余注册失败（例如同名指标的标签不一致）直接 panic，避免拦截器使用未注册的 collector 导致指标被静默丢弃
+func registerCollector[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	err := reg.Register(c)
+	if err == nil {
+		return c
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+	panic(fmt.Sprintf("interceptor: register gRPC metrics collector: %v", err))
+}
+
 // MetricsUnaryInterceptor 创建 gRPC metrics 拦截器
-func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+// 默认不统计 gRPC 健康检查请求，可通过 WithIncludeHealthChecks(true) 重新开启；
+// 通过 WithRecorder 指定 MetricsRecorder 时指标写入该 recorder 而不是 Prometheus；
+// 配置 WithSkipReplayMetrics(true) 时不统计携带 x-replay 标记的回放流量。
+// 请求 context 中存在有效的 span 时，耗时直方图会附带 trace_id exemplar。
+// 改变指标标签集合的选项需要通过 WithMetricsRegistry 指定 registry，否则会 panic
+func MetricsUnaryInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+	return metricsUnaryInterceptor(o, metricsRecord(o.metricsRegistry, o))
+}
+
+// MetricsUnaryInterceptorWithRegistry 创建 gRPC metrics 拦截器，指标注册到调用方提供的 registry 而不是默认 registry
+// 适用于同一进程内启动多个服务的集成测试，避免全局指标重复注册和状态泄漏；
+// reg 优先于 WithMetricsRegistry；通过 WithRecorder 指定 MetricsRecorder 时指标写入该 recorder，reg 不再使用
+func MetricsUnaryInterceptorWithRegistry(reg prometheus.Registerer, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+	return metricsUnaryInterceptor(o, metricsRecord(reg, o))
+}
+
+// metricsRecord 返回 metrics 拦截器使用的 recordFunc：设置了 recorder 时写入 recorder，否则写入 reg 中的 Prometheus 指标
+func metricsRecord(reg prometheus.Registerer, o *options) recordFunc {
+	if o.recorder != nil {
+		return recorderRecord(o.recorder)
+	}
+	return buildGRPCMetrics(reg, o).record
+}
+
+// LiteMetricsUnaryInterceptor 创建轻量的 gRPC metrics 拦截器
+// 只记录不带 method 标签的请求总数和耗时（GRPCLiteRequestTotal、GRPCLiteRequestDuration），按 ok、error 区分结果，
+// 适用于方法数量极多、按方法统计基数过高的高 QPS 内部服务
+func LiteMetricsUnaryInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+	return metricsUnaryInterceptor(o, recordLite)
+}
+
+// recordLite 将单次请求的结果写入不带 method 标签的轻量指标
+func recordLite(ctx context.Context, method string, code codes.Code, elapsed time.Duration) {
+	result := "ok"
+	if code != codes.OK {
+		result = "error"
+	}
+	GRPCLiteRequestTotal.WithLabelValues(result).Inc()
+	GRPCLiteRequestDuration.WithLabelValues(result).Observe(elapsed.Seconds())
+}
+
+// record 将单次请求的结果写入 Prometheus 指标
+func (m *grpcMetrics) record(ctx context.Context, method string, code codes.Code, elapsed time.Duration) {
+	duration := elapsed.Seconds()
+
+	labelValues := []string{method, code.String()}
+	if m.splitMethod {
+		service, name := splitFullMethod(method)
+		labelValues = []string{service, name, code.String()}
+	}
+	for _, l := range m.extraLabels {
+		labelValues = append(labelValues, l.value(ctx, code))
+	}
+
+	m.requestTotal.WithLabelValues(labelValues...).Inc()
+	if m.observeHistogram {
+		observeWithTraceExemplar(ctx, m.requestDuration.WithLabelValues(labelValues...), duration)
+	}
+	if m.requestSummary != nil {
+		m.requestSummary.WithLabelValues(labelValues...).Observe(duration)
+	}
+}
+
+// splitFullMethod 将 /pkg.Service/Method 形式的完整方法名拆分为 pkg.Service 和 Method
+// 格式不正确时 service 返回 unknown，method 返回原始字符串
+func splitFullMethod(fullMethod string) (service, method string) {
+	if strings.HasPrefix(fullMethod, "/") {
+		if i := strings.Index(fullMethod[1:], "/"); i > 0 {
+			service, method = fullMethod[1:i+1], fullMethod[i+2:]
+			if method != "" && !strings.Contains(method, "/") {
+				return service, method
+			}
+		}
+	}
+	return unknownLabelValue, fullMethod
+}
+
+// observeWithTraceExemplar 记录耗时，存在有效的 span context 时附带 trace_id exemplar，
+// 便于从延迟分桶跳转到对应的 trace；observer 不支持 exemplar 时退化为普通记录
+func observeWithTraceExemplar(ctx context.Context, observer prometheus.Observer, value float64) {
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		if eo, ok := observer.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(value, prometheus.Labels{"trace_id": sc.TraceID().String()})
+			return
+		}
+	}
+	observer.Observe(value)
+}
+
+// recordFunc 将单次请求的方法、状态码和耗时写入具体的指标后端
+type recordFunc func(ctx context.Context, method string, code codes.Code, elapsed time.Duration)
+
+// metricsUnaryInterceptor 创建 gRPC metrics 拦截器，负责计时、提取状态码和回调观察者，
+// 具体的指标写入由 record 完成
+func metricsUnaryInterceptor(o *options, record recordFunc) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		start := time.Now()
+		if handler == nil {
+			return nil, errNilHandler
+		}
+
+		ctx = o.enterChain(ctx)
+		method := fullMethodFromInfo(info)
+		if o.skipMetrics(method) || (o.skipReplayMetrics && isReplay(ctx)) {
+			return handler(ctx, req)
+		}
+
+		start := nowFunc()
 
 		// 调用处理器
 		resp, err := handler(ctx, req)
+		err = o.normalizeError(err)
 
 		// 记录 metrics
-		duration := time.Since(start).Seconds()
-		code := status.Code(err).String()
-		if err == nil {
-			code = codes.OK.String()
-		}
+		elapsed := since(start)
+		record(ctx, o.methodLabel(method), status.Code(err), elapsed)
 
-		metrics.GRPCRequestTotal.WithLabelValues(info.FullMethod, code).Inc()
-		metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code).Observe(duration)
+		// 回调自定义观察者
+		if o.observer != nil {
+			o.observer(contextWithMethod(ctx, method), method, elapsed, err)
+		}
 
 		return resp, err
 	}
 }
+
+// statusCodeString 返回 err 对应的 gRPC 状态码字符串，err 为 nil 时返回 OK
+func statusCodeString(err error) string {
+	if err == nil {
+		return codes.OK.String()
+	}
+	return status.Code(err).String()
+}
+
+// outcomeForCode 将状态码归类为便于告警的请求结果：
+// success、cancelled（客户端取消）、timeout（超过截止时间）、client_error（请求本身有误）或 server_error
+func outcomeForCode(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return "success"
+	case codes.Canceled:
+		return "cancelled"
+	case codes.DeadlineExceeded:
+		return "timeout"
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.ResourceExhausted, codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated:
+		return "client_error"
+	default:
+		return "server_error"
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// MetricsRecorder 请求指标的写入后端，便于接入 StatsD、Datadog 等非 Prometheus 的指标系统
+// method 为完整方法名，code 为 gRPC 状态码字符串，实现需要支持并发调用
+type MetricsRecorder interface {
+	// IncRequest 请求数加一
+	IncRequest(method, code string)
+	// ObserveDuration 记录请求耗时，d 的单位为秒
+	ObserveDuration(method, code string, d float64)
+}
+
+// PrometheusRecorder 返回写入 Prometheus 指标的 MetricsRecorder，opts 与 MetricsUnaryInterceptor 的指标选项含义相同，
+// 通过 WithRecorder 使用时与 MetricsUnaryInterceptor 默认写入的指标、exemplar 和附加标签完全一致
+func PrometheusRecorder(opts ...Option) MetricsRecorder {
+	o := newOptions(opts...)
+	return buildGRPCMetrics(o.metricsRegistry, o)
+}
+
+// IncRequest 实现 MetricsRecorder，附加标签取 unknown
+func (m *grpcMetrics) IncRequest(method, code string) {
+	m.requestTotal.WithLabelValues(m.labelValues(method, code)...).Inc()
+}
+
+// ObserveDuration 实现 MetricsRecorder，附加标签取 unknown
+func (m *grpcMetrics) ObserveDuration(method, code string, d float64) {
+	labelValues := m.labelValues(method, code)
+	if m.observeHistogram {
+		m.requestDuration.WithLabelValues(labelValues...).Observe(d)
+	}
+	if m.requestSummary != nil {
+		m.requestSummary.WithLabelValues(labelValues...).Observe(d)
+	}
+}
+
+// labelValues 返回没有请求上下文时的标签值，附加标签取 unknown
+func (m *grpcMetrics) labelValues(method, code string) []string {
+	labelValues := []string{method, code}
+	if m.splitMethod {
+		service, name := splitFullMethod(method)
+		labelValues = []string{service, name, code}
+	}
+	for range m.extraLabels {
+		labelValues = append(labelValues, unknownLabelValue)
+	}
+	return labelValues
+}
+
+// recorderRecord 将 MetricsRecorder 适配为 metrics 拦截器使用的 recordFunc
+// r 为 PrometheusRecorder 返回的指标集合时直接使用其 record，保留请求上下文中的 exemplar 和附加标签
+func recorderRecord(r MetricsRecorder) recordFunc {
+	if m, ok := r.(*grpcMetrics); ok {
+		return m.record
+	}
+	return func(ctx context.Context, method string, code codes.Code, elapsed time.Duration) {
+		r.IncRequest(method, code.String())
+		r.ObserveDuration(method, code.String(), elapsed.Seconds())
+	}
+}
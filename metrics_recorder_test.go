@@ -0,0 +1,160 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-anyway/framework-metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeRecorder 记录调用参数的 MetricsRecorder
+type fakeRecorder struct {
+	mu        sync.Mutex
+	requests  []string
+	durations []float64
+}
+
+func (r *fakeRecorder) IncRequest(method, code string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, method+" "+code)
+}
+
+func (r *fakeRecorder) ObserveDuration(method, code string, d float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.durations = append(r.durations, d)
+}
+
+func TestMetricsUnaryInterceptor_WithRecorder(t *testing.T) {
+	rec := &fakeRecorder{}
+	interceptor := MetricsUnaryInterceptor(WithRecorder(rec))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Recorded",
+	}
+
+	counter := metrics.GRPCRequestTotal.WithLabelValues("/test.Service/Recorded", codes.NotFound.String())
+	before := testutil.ToFloat64(counter)
+
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+
+	if len(rec.requests) != 1 || rec.requests[0] != "/test.Service/Recorded NotFound" {
+		t.Errorf("requests = %v, want [/test.Service/Recorded NotFound]", rec.requests)
+	}
+	if len(rec.durations) != 1 {
+		t.Errorf("durations = %v, want 1 observation", rec.durations)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 0 {
+		t.Errorf("prometheus counter delta = %v, want 0", got)
+	}
+}
+
+func TestPrometheusRecorder(t *testing.T) {
+	counter := metrics.GRPCRequestTotal.WithLabelValues("/test.Service/PromRecorder", codes.OK.String())
+	before := testutil.ToFloat64(counter)
+
+	rec := PrometheusRecorder()
+	rec.IncRequest("/test.Service/PromRecorder", codes.OK.String())
+	rec.ObserveDuration("/test.Service/PromRecorder", codes.OK.String(), 0.1)
+
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("counter delta = %v, want 1", got)
+	}
+}
+
+func TestMetricsUnaryInterceptorWithRegistry_WithRecorder(t *testing.T) {
+	rec := &fakeRecorder{}
+	reg := prometheus.NewRegistry()
+	interceptor := MetricsUnaryInterceptorWithRegistry(reg, WithRecorder(rec))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Recorded",
+	}
+
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	})
+
+	if len(rec.requests) != 1 || rec.requests[0] != "/test.Service/Recorded OK" {
+		t.Errorf("requests = %v, want [/test.Service/Recorded OK]", rec.requests)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	if len(families) != 0 {
+		t.Errorf("registry has %d metric families, want 0 when a recorder is set", len(families))
+	}
+}
+
+func TestPrometheusRecorder_TraceExemplar(t *testing.T) {
+	setupTestTracer(t)
+
+	interceptor := MetricsUnaryInterceptor(WithRecorder(PrometheusRecorder()))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/PromRecorderExemplar",
+	}
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+	_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var traceIDs []string
+	for _, mf := range families {
+		if mf.GetName() != "grpc_request_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			var matched bool
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "method" && lp.GetValue() == info.FullMethod {
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
+			for _, b := range m.GetHistogram().GetBucket() {
+				for _, lp := range b.GetExemplar().GetLabel() {
+					if lp.GetName() == "trace_id" {
+						traceIDs = append(traceIDs, lp.GetValue())
+					}
+				}
+			}
+		}
+	}
+
+	want := span.SpanContext().TraceID().String()
+	if len(traceIDs) != 1 || traceIDs[0] != want {
+		t.Errorf("exemplar trace IDs = %v, want [%s]", traceIDs, want)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequireMTLSUnaryInterceptor 创建 mTLS 校验拦截器
+// 只允许携带已验证客户端证书的 TLS 连接，其余请求（包括非 TLS 连接）返回 Unauthenticated。
+// 校验通过后，客户端证书的 Common Name 会放入 context，处理器可通过 PeerCommonNameFromContext 获取并据此授权
+func RequireMTLSUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		commonName, ok := verifiedPeerCommonName(ctx)
+		if !ok {
+			GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "mtls_required").Inc()
+			return nil, status.Error(codes.Unauthenticated, "verified client certificate required")
+		}
+
+		return handler(contextWithPeerCommonName(ctx, commonName), req)
+	}
+}
+
+// verifiedPeerCommonName 返回对端已验证客户端证书的 Common Name
+// 没有对端信息、非 TLS 连接或客户端证书未验证时返回 false
+func verifiedPeerCommonName(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return "", false
+	}
+
+	var tlsInfo credentials.TLSInfo
+	switch info := p.AuthInfo.(type) {
+	case credentials.TLSInfo:
+		tlsInfo = info
+	case *credentials.TLSInfo:
+		if info == nil {
+			return "", false
+		}
+		tlsInfo = *info
+	default:
+		return "", false
+	}
+
+	if len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName, true
+}
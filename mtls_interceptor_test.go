@@ -0,0 +1,110 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequireMTLSUnaryInterceptor(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "billing-service"}}
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+		wantCN   string
+	}{
+		{
+			name:     "no peer",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "nil auth info",
+			ctx:      peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "insecure connection",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{
+				Addr:     addr,
+				AuthInfo: fakeAuthInfo{},
+			}),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "tls without client certificate",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{
+				Addr:     addr,
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{}},
+			}),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "verified client certificate",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{
+				Addr: addr,
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+					PeerCertificates: []*x509.Certificate{cert},
+					VerifiedChains:   [][]*x509.Certificate{{cert}},
+				}},
+			}),
+			wantCode: codes.OK,
+			wantCN:   "billing-service",
+		},
+	}
+
+	interceptor := RequireMTLSUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Internal",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCN string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				gotCN = PeerCommonNameFromContext(ctx)
+				return "response", nil
+			}
+
+			_, err := interceptor(tt.ctx, nil, info, handler)
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			if gotCN != tt.wantCN {
+				t.Errorf("PeerCommonNameFromContext() = %q, want %q", gotCN, tt.wantCN)
+			}
+		})
+	}
+}
+
+// fakeAuthInfo 模拟非 TLS 的连接认证信息
+type fakeAuthInfo struct{}
+
+func (fakeAuthInfo) AuthType() string { return "insecure" }
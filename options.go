@@ -0,0 +1,876 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"hash/fnv"
+	"regexp"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-anyway/framework-trace"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultMaxRequestIDLength 上游传入的 requestID 默认最大长度
+	defaultMaxRequestIDLength = 128
+	// defaultStartMessage 请求开始日志的默认消息
+	defaultStartMessage = "gRPC request started"
+	// defaultCompleteMessage 请求完成日志的默认消息
+	defaultCompleteMessage = "gRPC request completed"
+	// defaultFailMessage 请求失败日志的默认消息
+	defaultFailMessage = "gRPC request failed"
+	// accessLogMessage 访问日志的消息
+	accessLogMessage = "gRPC access"
+	// defaultMaxAttempts 客户端重试默认的最大尝试次数（包含首次调用）
+	defaultMaxAttempts = 3
+	// defaultRetryBackoff 客户端重试默认的初始退避时间
+	defaultRetryBackoff = 50 * time.Millisecond
+	// logSampleBuckets 日志采样时 traceID 哈希取模的桶数
+	logSampleBuckets = 10000
+)
+
+// defaultRetryCodes 客户端默认重试的状态码
+var defaultRetryCodes = []codes.Code{codes.Unavailable}
+
+// Option 拦截器配置项
+type Option func(*options)
+
+// ObserverFunc 请求结束后的回调函数
+// ctx 中携带 traceID 和方法名，可通过 log.TraceIDFromContext 和 MethodFromContext 获取
+type ObserverFunc func(ctx context.Context, fullMethod string, duration time.Duration, err error)
+
+// SpanAttributeFunc 根据请求动态计算 span 属性的函数
+type SpanAttributeFunc func(ctx context.Context, req interface{}) []attribute.KeyValue
+
+// LatencyMode 请求耗时指标的记录方式
+type LatencyMode int
+
+const (
+	// LatencyHistogram 仅记录直方图（默认）
+	LatencyHistogram LatencyMode = iota + 1
+	// LatencySummary 仅记录 Summary
+	LatencySummary
+	// LatencyHistogramAndSummary 同时记录直方图和 Summary
+	LatencyHistogramAndSummary
+)
+
+// options 拦截器配置
+type options struct {
+	// observer 请求结束后的回调
+	observer ObserverFunc
+	// requireTrace 是否要求请求必须携带追踪上下文
+	requireTrace bool
+	// spanAttributes 附加到每个 span 的静态属性
+	spanAttributes []attribute.KeyValue
+	// spanAttributeFunc 根据请求动态计算 span 属性
+	spanAttributeFunc SpanAttributeFunc
+	// includeHealthChecks 是否在 metrics 中统计健康检查请求
+	includeHealthChecks bool
+	// traceTrailers 是否通过 trailer 返回 traceID 和 spanID
+	traceTrailers bool
+	// latencyMode 请求耗时指标的记录方式
+	latencyMode LatencyMode
+	// summaryObjectives Summary 的分位数目标
+	summaryObjectives map[float64]float64
+	// maxRequestIDLength 上游传入的 requestID 最大长度
+	maxRequestIDLength int
+	// requestIDPattern 上游传入的 requestID 需要匹配的格式，为 nil 时不校验格式
+	requestIDPattern *regexp.Regexp
+	// knownMethods 已知方法集合，为 nil 时不限制 metrics 的方法标签
+	knownMethods map[string]struct{}
+	// requestFieldAttrs 从请求 proto 字段提取 span 属性的映射，key 为字段名，value 为属性名
+	requestFieldAttrs map[string]string
+	// maxAttempts 客户端重试的最大尝试次数（包含首次调用）
+	maxAttempts int
+	// retryCodes 客户端重试的状态码
+	retryCodes []codes.Code
+	// retryBackoff 客户端重试的初始退避时间，每次重试翻倍
+	retryBackoff time.Duration
+	// callerMetadataKey 读取调用方标识的 metadata key，为空时不添加 caller 标签
+	callerMetadataKey string
+	// logPayloads 是否在完成日志中记录请求和响应内容
+	logPayloads bool
+	// payloadEncoder 编码日志中请求和响应内容的函数，为 nil 时使用 protojson
+	payloadEncoder PayloadEncoder
+	// logSampleRate 记录请求开始、完成日志的 trace 比例，取值 [0, 1]
+	logSampleRate float64
+	// defaultErrorCode 处理器返回非 gRPC status 错误时转换使用的状态码，为 OK 时不转换
+	defaultErrorCode codes.Code
+	// requireTenant 缺少租户 header 时是否拒绝请求
+	requireTenant bool
+	// requestIDFromSpan 缺少 requestID 时是否使用当前 span ID 作为 requestID
+	requestIDFromSpan bool
+	// includeReflection 是否追踪和统计 gRPC 服务反射请求
+	includeReflection bool
+	// tracerName、tracerVersion 创建 span 使用的 tracer 名称和版本，名称为空时使用 framework-trace 的默认 tracer
+	tracerName    string
+	tracerVersion string
+	// spanNameFormatter 根据完整方法名生成 span 名称，为 nil 时直接使用完整方法名
+	spanNameFormatter func(fullMethod string) string
+	// outcomeLabel 是否在请求指标中添加由状态码归类得到的 outcome 标签
+	outcomeLabel bool
+	// noPropagationMethods 不接受上游追踪上下文、总是创建新根 span 的方法集合
+	noPropagationMethods map[string]struct{}
+	// missingDeadlineCode 请求没有 deadline 时返回的状态码
+	missingDeadlineCode codes.Code
+	// defaultDeadline 请求没有 deadline 时设置的默认时限，大于 0 时不再拒绝请求
+	defaultDeadline time.Duration
+	// constLabels 附加到请求指标的常量标签，例如 version、region
+	constLabels map[string]string
+	// propagationWarnings 客户端出站调用没有父 span 时是否记录警告
+	propagationWarnings bool
+	// numericStatusAttribute 是否额外以整数形式记录状态码属性 rpc.grpc.status_code
+	numericStatusAttribute bool
+	// maxFieldLength trace 拦截器日志中字符串字段的最大字符数，<= 0 时不限制
+	maxFieldLength int
+	// interceptorTiming 默认服务端拦截器链是否记录每个拦截器自身的耗时
+	interceptorTiming bool
+	// keepErrors 是否强制保留失败请求的 span
+	keepErrors bool
+	// keepSlowerThan 耗时不低于该值的请求强制保留 span，<= 0 时不按耗时保留
+	keepSlowerThan time.Duration
+	// methodSendLimits 按方法设置的流响应数量上限，覆盖 MaxStreamSendInterceptor 的默认上限
+	methodSendLimits map[string]int
+	// requestIDFormat 上游未传入有效 requestID 时生成的 requestID 格式
+	requestIDFormat RequestIDFormat
+	// requireSchemaVersion 缺少 schema 版本 header 时是否拒绝请求
+	requireSchemaVersion bool
+	// baggageMetricLabels 作为请求指标标签记录的 baggage key
+	baggageMetricLabels []string
+	// skipReplayMetrics 是否不统计携带 x-replay 标记的请求
+	skipReplayMetrics bool
+	// traceIDInErrors 是否在返回的错误详情中附加 traceID
+	traceIDInErrors bool
+	// signatureExemptMethods 跳过请求签名校验的方法
+	signatureExemptMethods map[string]struct{}
+	// syncOnError 记录失败日志后是否立即刷新 logger 缓冲
+	syncOnError bool
+	// splitMethodLabels 是否在请求指标中以 service、method 两个标签代替完整方法名
+	splitMethodLabels bool
+	// metricsRegistry metrics 拦截器注册指标的 registry，为 nil 时使用默认 registry
+	metricsRegistry prometheus.Registerer
+	// recorder 自定义的指标写入后端，为 nil 时使用 Prometheus
+	recorder MetricsRecorder
+	// chainName 拦截器在链中的名称，用于调试拦截器执行顺序
+	chainName string
+	// chainLogOnce 保证拦截器位置只记录一次
+	chainLogOnce sync.Once
+	// clientLogging 客户端拦截器是否记录调用日志
+	clientLogging bool
+	// startMessage、completeMessage、failMessage 请求开始、完成、失败日志的消息
+	startMessage    string
+	completeMessage string
+	failMessage     string
+	// forceNewRoot 没有提取到远程追踪上下文时是否强制创建根 span
+	forceNewRoot bool
+	// accessLog 是否为每个请求记录一条固定字段的访问日志
+	accessLog bool
+	// retryBudgetRatio 每次成功调用为重试预算存入的令牌数，为 0 时不限制重试
+	retryBudgetRatio float64
+	// retryBudgetMinPerSec 不受预算限制、每秒始终允许的重试次数
+	retryBudgetMinPerSec int
+	// handlerBoundaryEvents 是否在 span 上记录处理器起止事件
+	handlerBoundaryEvents bool
+	// codeLevels 按状态码指定请求完成日志的级别
+	codeLevels map[codes.Code]zapcore.Level
+}
+
+// newOptions 创建配置并应用给定的选项
+func newOptions(opts ...Option) *options {
+	o := &options{
+		maxRequestIDLength:  defaultMaxRequestIDLength,
+		maxAttempts:         defaultMaxAttempts,
+		retryCodes:          defaultRetryCodes,
+		retryBackoff:        defaultRetryBackoff,
+		logSampleRate:       1,
+		startMessage:        defaultStartMessage,
+		completeMessage:     defaultCompleteMessage,
+		failMessage:         defaultFailMessage,
+		missingDeadlineCode: codes.InvalidArgument,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// skipMetrics 判断方法是否应跳过 metrics 记录
+func (o *options) skipMetrics(method string) bool {
+	if !o.includeHealthChecks && isHealthCheckMethod(method) {
+		return true
+	}
+	return o.skipTrace(method)
+}
+
+// skipTrace 判断方法是否应跳过追踪
+func (o *options) skipTrace(method string) bool {
+	return !o.includeReflection && isReflectionMethod(method)
+}
+
+// resolvedLatencyMode 返回实际使用的耗时记录方式
+// 未显式指定时，设置了 Summary 分位数目标则同时记录直方图和 Summary，否则仅记录直方图
+func (o *options) resolvedLatencyMode() LatencyMode {
+	if o.latencyMode != 0 {
+		return o.latencyMode
+	}
+	if o.summaryObjectives != nil {
+		return LatencyHistogramAndSummary
+	}
+	return LatencyHistogram
+}
+
+// methodLabel 返回 metrics 使用的方法标签值，配置了已知方法列表时未知方法统一记为 unknown
+func (o *options) methodLabel(method string) string {
+	if o.knownMethods == nil {
+		return method
+	}
+	if _, ok := o.knownMethods[method]; ok {
+		return method
+	}
+	return unknownMethod
+}
+
+// noPropagation 判断方法是否忽略上游传入的追踪上下文
+func (o *options) noPropagation(method string) bool {
+	_, ok := o.noPropagationMethods[method]
+	return ok
+}
+
+// setNumericStatus 启用 WithNumericStatusAttribute 时以整数形式记录 err 对应的状态码
+func (o *options) setNumericStatus(span oteltrace.Span, err error) {
+	if o.numericStatusAttribute {
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(status.Code(err))))
+	}
+}
+
+// metricLabels 返回根据配置在 method、code 之外附加的指标标签
+func (o *options) metricLabels() []metricLabel {
+	var labels []metricLabel
+	if o.callerMetadataKey != "" {
+		key := o.callerMetadataKey
+		labels = append(labels, metricLabel{
+			name: "caller",
+			value: func(ctx context.Context, _ codes.Code) string {
+				md, _ := metadata.FromIncomingContext(ctx)
+				if caller := lookupMetadata(md, key); caller != "" {
+					return caller
+				}
+				return unknownLabelValue
+			},
+		})
+	}
+	for _, key := range o.baggageMetricLabels {
+		labels = append(labels, metricLabel{
+			name: baggageLabelName(key),
+			value: func(ctx context.Context, _ codes.Code) string {
+				if value := baggage.FromContext(ctx).Member(key).Value(); value != "" {
+					return value
+				}
+				return unknownLabelValue
+			},
+		})
+	}
+	if o.outcomeLabel {
+		labels = append(labels, metricLabel{
+			name: "outcome",
+			value: func(_ context.Context, code codes.Code) string {
+				return outcomeForCode(code)
+			},
+		})
+	}
+	return labels
+}
+
+// logSampled 判断 traceID 对应的请求是否记录正常日志
+// 对 traceID 做哈希取模，同一条 trace 在所有服务中的采样结果一致
+func (o *options) logSampled(traceID string) bool {
+	if o.logSampleRate >= 1 {
+		return true
+	}
+	if o.logSampleRate <= 0 {
+		return false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(traceID))
+	return float64(h.Sum32()%logSampleBuckets) < o.logSampleRate*logSampleBuckets
+}
+
+// normalizeError 将处理器返回的非 gRPC status 错误转换为 defaultErrorCode，保留原始错误信息
+func (o *options) normalizeError(err error) error {
+	if err == nil || o.defaultErrorCode == codes.OK {
+		return err
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(o.defaultErrorCode, err.Error())
+}
+
+// spanName 返回方法对应的 span 名称
+func (o *options) spanName(method string) string {
+	if o.spanNameFormatter == nil {
+		return method
+	}
+	return o.spanNameFormatter(method)
+}
+
+// startSpan 开始新的 span，设置了 tracerName 时使用对应的命名 tracer
+func (o *options) startSpan(ctx context.Context, name string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	if o.tracerName == "" {
+		return trace.StartSpan(ctx, name, opts...)
+	}
+	tracer := otel.Tracer(o.tracerName, oteltrace.WithInstrumentationVersion(o.tracerVersion))
+	return tracer.Start(ctx, name, opts...)
+}
+
+// completionLevel 返回请求完成日志的级别，未在 codeLevels 中配置的状态码使用默认级别
+func (o *options) completionLevel(err error) zapcore.Level {
+	if level, ok := o.codeLevels[status.Code(err)]; ok {
+		return level
+	}
+	return completionLevel(err)
+}
+
+// retryable 判断状态码是否需要重试
+func (o *options) retryable(code codes.Code) bool {
+	for _, c := range o.retryCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// validRequestID 校验上游传入的 requestID，防止换行符等控制字符或超长字符串破坏日志
+func (o *options) validRequestID(id string) bool {
+	if id == "" || len(id) > o.maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return o.requestIDPattern == nil || o.requestIDPattern.MatchString(id)
+}
+
+// WithObserver 设置请求结束后的回调，可用于记录自定义业务指标
+func WithObserver(fn ObserverFunc) Option {
+	return func(o *options) {
+		o.observer = fn
+	}
+}
+
+// WithRequireTrace 设置是否要求请求必须携带有效的追踪上下文，默认关闭
+// 开启后，缺少追踪上下文的请求将返回 FailedPrecondition，适用于网格内必须传递追踪信息的内部服务
+func WithRequireTrace(require bool) Option {
+	return func(o *options) {
+		o.requireTrace = require
+	}
+}
+
+// WithSpanAttributes 设置附加到每个 span 的静态属性，例如 service.version、deployment.environment
+// 这些属性与内置的 rpc.method、rpc.status_code 等属性合并，不会覆盖它们
+func WithSpanAttributes(attrs ...attribute.KeyValue) Option {
+	return func(o *options) {
+		o.spanAttributes = append(o.spanAttributes, attrs...)
+	}
+}
+
+// WithSpanAttributeFunc 设置根据请求动态计算 span 属性的函数，例如从 metadata 中提取租户信息
+// 该函数在 span 开始后、处理器调用前执行
+func WithSpanAttributeFunc(fn SpanAttributeFunc) Option {
+	return func(o *options) {
+		o.spanAttributeFunc = fn
+	}
+}
+
+// WithIncludeHealthChecks 设置 metrics 是否统计 gRPC 健康检查请求
+// 默认不统计 /grpc.health.v1.Health/Check 和 /grpc.health.v1.Health/Watch
+func WithIncludeHealthChecks(include bool) Option {
+	return func(o *options) {
+		o.includeHealthChecks = include
+	}
+}
+
+// WithTraceTrailers 设置是否在处理器完成后通过 trailer 返回 x-trace-id 和 x-span-id，默认关闭
+// 即使响应 header 已经发送，客户端也可以通过 trailer 关联服务端的 span
+func WithTraceTrailers(enabled bool) Option {
+	return func(o *options) {
+		o.traceTrailers = enabled
+	}
+}
+
+// WithLatencySummary 设置请求耗时 Summary 的分位数目标，例如 {0.5: 0.05, 0.99: 0.001}
+// 未通过 WithLatencyMode 指定记录方式时，Summary 与直方图同时记录。
+// 默认 registry 中的 GRPCRequestLatencySummary 使用默认分位数目标，自定义目标需要配合 MetricsUnaryInterceptorWithRegistry
+func WithLatencySummary(objectives map[float64]float64) Option {
+	return func(o *options) {
+		o.summaryObjectives = objectives
+	}
+}
+
+// WithLatencyMode 设置请求耗时指标的记录方式：直方图、Summary 或两者
+func WithLatencyMode(mode LatencyMode) Option {
+	return func(o *options) {
+		o.latencyMode = mode
+	}
+}
+
+// WithMaxRequestIDLength 设置上游传入的 requestID 最大长度，默认 128
+// 超过长度的 requestID 会被丢弃并重新生成
+func WithMaxRequestIDLength(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxRequestIDLength = n
+		}
+	}
+}
+
+// WithRequestIDPattern 设置上游传入的 requestID 需要匹配的格式
+// 不匹配的 requestID 会被丢弃并重新生成
+func WithRequestIDPattern(pattern *regexp.Regexp) Option {
+	return func(o *options) {
+		o.requestIDPattern = pattern
+	}
+}
+
+// WithKnownMethods 设置 metrics 的已知方法列表，不在列表中的方法统一记为 unknown 标签
+// 用于通用代理等会收到任意方法名的服务，防止标签基数爆炸；未设置时行为不变
+func WithKnownMethods(methods ...string) Option {
+	return func(o *options) {
+		if o.knownMethods == nil {
+			o.knownMethods = make(map[string]struct{}, len(methods))
+		}
+		for _, m := range methods {
+			o.knownMethods[m] = struct{}{}
+		}
+	}
+}
+
+// WithRequestFieldAttributes 设置从请求中提取为 span 属性的 proto 字段，key 为顶层字段名，value 为属性名
+// 例如 {"order_id": "app.order_id"}；只提取标量字段，嵌套消息、repeated 和 map 字段会被跳过
+func WithRequestFieldAttributes(fieldToAttr map[string]string) Option {
+	return func(o *options) {
+		o.requestFieldAttrs = fieldToAttr
+	}
+}
+
+// WithMaxAttempts 设置客户端重试的最大尝试次数（包含首次调用），默认 3
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxAttempts = n
+		}
+	}
+}
+
+// WithRetryCodes 设置客户端需要重试的状态码，默认只重试 Unavailable
+func WithRetryCodes(c ...codes.Code) Option {
+	return func(o *options) {
+		o.retryCodes = c
+	}
+}
+
+// WithRetryBackoff 设置客户端重试的初始退避时间，每次重试翻倍，默认 50ms
+func WithRetryBackoff(d time.Duration) Option {
+	return func(o *options) {
+		if d >= 0 {
+			o.retryBackoff = d
+		}
+	}
+}
+
+// WithCallerLabel 设置从 metadata 中读取调用方标识的 key（例如 x-caller-service），并作为 caller 标签记录到请求指标中
+// 缺失的调用方记为 unknown；默认不添加该标签以避免基数变化
+func WithCallerLabel(metadataKey string) Option {
+	return func(o *options) {
+		o.callerMetadataKey = metadataKey
+	}
+}
+
+// WithPayloadLogging 设置是否在请求完成日志中记录请求和响应内容（request、response 字段），默认关闭
+// 只记录 proto 消息，编码方式可通过 WithPayloadLogEncoder 指定
+func WithPayloadLogging(enabled bool) Option {
+	return func(o *options) {
+		o.logPayloads = enabled
+	}
+}
+
+// WithPayloadLogEncoder 设置日志中请求和响应内容的编码函数，默认使用 protojson 完整输出
+// 可使用内置的 CompactPayloadEncoder 缩短日志行
+func WithPayloadLogEncoder(fn PayloadEncoder) Option {
+	return func(o *options) {
+		o.payloadEncoder = fn
+	}
+}
+
+// WithLogSampleRate 设置记录请求开始、完成日志的 trace 比例，取值 [0, 1]，默认 1（全部记录）
+// 按 traceID 哈希确定性采样，同一条 trace 的日志要么全部保留要么全部丢弃；失败请求的日志不受采样影响
+func WithLogSampleRate(rate float64) Option {
+	return func(o *options) {
+		o.logSampleRate = rate
+	}
+}
+
+// WithDefaultErrorCode 设置处理器返回非 gRPC status 错误（例如 errors.New）时使用的状态码，默认不转换（客户端收到 Unknown）
+// 转换后保留原始错误信息，已经是 gRPC status 的错误原样返回
+func WithDefaultErrorCode(c codes.Code) Option {
+	return func(o *options) {
+		o.defaultErrorCode = c
+	}
+}
+
+// WithRequireTenant 设置 TenantUnaryInterceptor 在缺少租户 header 时是否返回 InvalidArgument，默认放行
+func WithRequireTenant(require bool) Option {
+	return func(o *options) {
+		o.requireTenant = require
+	}
+}
+
+// WithRequestIDFromSpan 设置上游未传入有效 requestID 时是否使用当前 span ID 作为 requestID，默认关闭
+// 开启后日志与链路可以通过同一个 ID 关联；没有有效 span 时仍随机生成
+func WithRequestIDFromSpan(enabled bool) Option {
+	return func(o *options) {
+		o.requestIDFromSpan = enabled
+	}
+}
+
+// WithIncludeReflection 设置是否追踪和统计 gRPC 服务反射请求（/grpc.reflection.*），默认不记录
+// 避免开发工具的反射流量混入生产环境的链路和指标
+func WithIncludeReflection(include bool) Option {
+	return func(o *options) {
+		o.includeReflection = include
+	}
+}
+
+// WithTracerName 设置创建 span 使用的 tracer 名称和版本（instrumentation scope），便于在后端按来源归类 span
+// 未设置时使用 framework-trace 的默认 tracer
+func WithTracerName(name, version string) Option {
+	return func(o *options) {
+		o.tracerName = name
+		o.tracerVersion = version
+	}
+}
+
+// WithSpanNameFormatter 设置 span 名称的生成函数，例如将 /pkg.Service/Method 转换为 Service/Method
+// 完整方法名仍会记录在 rpc.method 属性中
+func WithSpanNameFormatter(fn func(fullMethod string) string) Option {
+	return func(o *options) {
+		o.spanNameFormatter = fn
+	}
+}
+
+// WithOutcomeLabel 设置是否在请求指标中添加 outcome 标签，取值为 success、client_error、server_error、cancelled、timeout，默认关闭
+// 便于区分客户端取消与超时等情况做 SLO 告警
+func WithOutcomeLabel(enabled bool) Option {
+	return func(o *options) {
+		o.outcomeLabel = enabled
+	}
+}
+
+// WithChainName 设置拦截器在链中的名称，用于排查拦截器执行顺序，默认不设置
+// 设置后拦截器首次执行时以 debug 级别记录其在链中的位置，处理器可通过 InterceptorChainFromContext 获取完整的执行顺序
+func WithChainName(name string) Option {
+	return func(o *options) {
+		o.chainName = name
+	}
+}
+
+// WithClientLogging 设置 TraceUnaryClientInterceptor 是否在调用结束后记录日志（方法、注入的 traceID 和状态码），默认关闭
+// 便于将客户端日志与服务端 span 关联
+func WithClientLogging(enabled bool) Option {
+	return func(o *options) {
+		o.clientLogging = enabled
+	}
+}
+
+// WithStartMessage 设置请求开始日志的消息，默认 "gRPC request started"
+func WithStartMessage(msg string) Option {
+	return func(o *options) {
+		if msg != "" {
+			o.startMessage = msg
+		}
+	}
+}
+
+// WithCompleteMessage 设置请求完成日志的消息，默认 "gRPC request completed"
+func WithCompleteMessage(msg string) Option {
+	return func(o *options) {
+		if msg != "" {
+			o.completeMessage = msg
+		}
+	}
+}
+
+// WithFailMessage 设置请求失败日志的消息，默认 "gRPC request failed"
+func WithFailMessage(msg string) Option {
+	return func(o *options) {
+		if msg != "" {
+			o.failMessage = msg
+		}
+	}
+}
+
+// WithForceNewRoot 设置服务端在没有提取到有效的远程追踪上下文时是否强制创建新的根 span，默认关闭
+// 适用于上游负载均衡会剥离追踪 header 的部署，避免 span 挂在 context 中不相关的本地 span 下
+func WithForceNewRoot(enabled bool) Option {
+	return func(o *options) {
+		o.forceNewRoot = enabled
+	}
+}
+
+// WithAccessLog 设置是否在请求完成时记录一条固定字段的访问日志（消息为 "gRPC access"），默认关闭
+// 字段固定为 method、code、duration_ms、trace_id、request_id、peer，不受日志采样和开始、完成日志的影响
+func WithAccessLog(enabled bool) Option {
+	return func(o *options) {
+		o.accessLog = enabled
+	}
+}
+
+// WithRetryBudget 为客户端重试设置令牌桶预算，防止故障期间重试放大流量，默认不限制
+// 每次成功调用存入 ratio 个令牌（例如 0.1 表示重试量约为成功调用的 10%），每次重试消耗 1 个令牌；
+// 此外每秒始终允许 minPerSec 次重试。预算耗尽时直接返回最后一次错误，并计入 GRPCRetryBudgetExhaustedTotal
+func WithRetryBudget(ratio float64, minPerSec int) Option {
+	return func(o *options) {
+		if ratio >= 0 && minPerSec >= 0 {
+			o.retryBudgetRatio = ratio
+			o.retryBudgetMinPerSec = minPerSec
+		}
+	}
+}
+
+// WithHandlerBoundaryEvents 设置服务端是否在 span 上记录 handler.start、handler.end 事件，默认关闭
+// 用于在链路中区分拦截器、排队耗时与处理器本身的耗时
+func WithHandlerBoundaryEvents(enabled bool) Option {
+	return func(o *options) {
+		o.handlerBoundaryEvents = enabled
+	}
+}
+
+// WithCodeLevelMapping 设置按状态码选择请求完成日志的级别，例如 {codes.NotFound: zapcore.InfoLevel}
+// 未配置的状态码使用默认级别：OK 为 Info，其余为 Error
+func WithCodeLevelMapping(m map[codes.Code]zapcore.Level) Option {
+	return func(o *options) {
+		o.codeLevels = m
+	}
+}
+
+// WithRecorder 设置 MetricsUnaryInterceptor 写入指标的后端，默认使用 framework-metrics 的 Prometheus 指标
+// 设置后 WithLatencyMode、WithCallerLabel 等只作用于 Prometheus 指标的配置不再生效，需要这些配置时传给 PrometheusRecorder
+func WithRecorder(r MetricsRecorder) Option {
+	return func(o *options) {
+		o.recorder = r
+	}
+}
+
+// WithNoPropagationMethods 设置不接受上游追踪上下文的方法（完整方法名），可多次调用累加
+// 这些方法忽略 traceparent 和 x-trace-id 等 header，总是创建新的根 span，防止外部调用方向敏感的内部方法注入任意 traceID
+func WithNoPropagationMethods(methods ...string) Option {
+	return func(o *options) {
+		if o.noPropagationMethods == nil {
+			o.noPropagationMethods = make(map[string]struct{}, len(methods))
+		}
+		for _, m := range methods {
+			o.noPropagationMethods[m] = struct{}{}
+		}
+	}
+}
+
+// WithMissingDeadlineCode 设置 RequireDeadlineUnaryInterceptor 拒绝没有 deadline 的请求时返回的状态码，默认 InvalidArgument
+func WithMissingDeadlineCode(code codes.Code) Option {
+	return func(o *options) {
+		if code != codes.OK {
+			o.missingDeadlineCode = code
+		}
+	}
+}
+
+// WithDefaultDeadline 设置 RequireDeadlineUnaryInterceptor 为没有 deadline 的请求设置默认时限而不是拒绝，
+// 便于在全量拒绝前逐步推动客户端设置 deadline。d <= 0 时恢复为拒绝
+func WithDefaultDeadline(d time.Duration) Option {
+	return func(o *options) {
+		o.defaultDeadline = d
+	}
+}
+
+// WithConstLabels 设置附加到请求指标的常量标签，例如 {"version": "v1.2.3", "region": "us-east-1"}，用于灰度发布时按版本对比错误率
+// Prometheus 的常量标签在创建指标时确定，因此会为该配置单独创建指标，同一 registry 中的所有 metrics 拦截器应使用相同的常量标签名
+func WithConstLabels(labels map[string]string) Option {
+	return func(o *options) {
+		o.constLabels = labels
+	}
+}
+
+// WithPropagationWarnings 设置客户端 trace 拦截器在出站调用的 context 中没有有效 span 时是否记录警告日志，默认关闭
+// 用于在开发、测试环境中发现使用 context.Background() 代替请求 ctx 导致的追踪链路断开，不建议在生产环境开启
+func WithPropagationWarnings(enabled bool) Option {
+	return func(o *options) {
+		o.propagationWarnings = enabled
+	}
+}
+
+// WithNumericStatusAttribute 设置 trace 拦截器是否在 rpc.status_code 字符串属性之外，额外以整数形式记录 rpc.grpc.status_code 属性，默认关闭
+// 便于在追踪后端中按状态码数值范围查询
+func WithNumericStatusAttribute(enabled bool) Option {
+	return func(o *options) {
+		o.numericStatusAttribute = enabled
+	}
+}
+
+// WithMaxFieldLength 设置 trace 拦截器输出日志中字符串字段（payload、错误信息、处理器追加的字段等）的最大字符数，默认不限制
+// 超出的部分被截断并追加 "...(truncated)" 标记，保证日志行长度有界、JSON 格式完整
+func WithMaxFieldLength(n int) Option {
+	return func(o *options) {
+		o.maxFieldLength = n
+	}
+}
+
+// WithInterceptorTiming 设置 DefaultUnaryServerInterceptors 是否将每个拦截器自身的耗时记录到 GRPCInterceptorDuration，默认关闭
+// 用于定位拦截器链中增加延迟的拦截器；每个请求会为每个拦截器额外计时，有一定开销
+func WithInterceptorTiming(enabled bool) Option {
+	return func(o *options) {
+		o.interceptorTiming = enabled
+	}
+}
+
+// WithKeepErrors 设置服务端 trace 拦截器是否强制保留失败请求的 span，即使头部采样丢弃了它，默认关闭
+// 需要 TracerProvider 使用 KeepSampler 包装采样器，详见 KeepSampler
+func WithKeepErrors(enabled bool) Option {
+	return func(o *options) {
+		o.keepErrors = enabled
+	}
+}
+
+// WithKeepSlowerThan 设置服务端 trace 拦截器强制保留耗时不低于 d 的请求的 span，d <= 0 时关闭，默认关闭
+// 需要 TracerProvider 使用 KeepSampler 包装采样器，详见 KeepSampler
+func WithKeepSlowerThan(d time.Duration) Option {
+	return func(o *options) {
+		o.keepSlowerThan = d
+	}
+}
+
+// WithMethodSendLimits 为 MaxStreamSendInterceptor 按完整方法名设置单个流的最大响应消息数，覆盖默认上限，<= 0 表示该方法不限制
+func WithMethodSendLimits(limits map[string]int) Option {
+	return func(o *options) {
+		o.methodSendLimits = make(map[string]int, len(limits))
+		for method, limit := range limits {
+			o.methodSendLimits[method] = limit
+		}
+	}
+}
+
+// WithRequestIDFormat 设置上游未传入有效 requestID 时生成的 requestID 格式，默认 RequestIDHex（32 位十六进制）
+func WithRequestIDFormat(format RequestIDFormat) Option {
+	return func(o *options) {
+		o.requestIDFormat = format
+	}
+}
+
+// WithRequireSchemaVersion 设置 SchemaVersionUnaryInterceptor 在缺少版本 header 时是否拒绝请求，默认放行
+func WithRequireSchemaVersion(require bool) Option {
+	return func(o *options) {
+		o.requireSchemaVersion = require
+	}
+}
+
+// WithBaggageMetricLabels 设置将哪些 OTel baggage 成员（例如 priority）作为标签记录到请求指标中，缺失的值记为 unknown
+// 标签名为 baggage key 中非字母、数字的字符替换为下划线后的结果；只记录显式列出的 key 以控制基数
+func WithBaggageMetricLabels(keys ...string) Option {
+	return func(o *options) {
+		o.baggageMetricLabels = append([]string(nil), keys...)
+	}
+}
+
+// baggageLabelName 将 baggage key 转换为合法的 Prometheus 标签名
+func baggageLabelName(key string) string {
+	b := []byte(key)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || (i > 0 && c >= '0' && c <= '9')) {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// WithSkipReplayMetrics 设置 metrics 拦截器是否跳过携带 x-replay 标记的回放、测试流量，默认关闭（所有流量正常统计）
+// 开启后回放流量不进入生产指标和 SLO，trace 拦截器仍会记录这些请求并设置 replay=true 属性
+func WithSkipReplayMetrics(skip bool) Option {
+	return func(o *options) {
+		o.skipReplayMetrics = skip
+	}
+}
+
+// WithTraceIDInErrors 设置服务端 trace 拦截器是否在失败请求返回的 status details 中追加携带 trace_id 的 ErrorInfo，默认关闭
+// 已有的 details 会保留，客户端可通过 TraceIDFromError 提取 traceID 用于问题反馈
+func WithTraceIDInErrors(enabled bool) Option {
+	return func(o *options) {
+		o.traceIDInErrors = enabled
+	}
+}
+
+// WithSignatureExemptMethods 设置 SignatureVerifyUnaryInterceptor 跳过签名校验的方法（完整方法名），可多次调用累加
+func WithSignatureExemptMethods(methods ...string) Option {
+	return func(o *options) {
+		if o.signatureExemptMethods == nil {
+			o.signatureExemptMethods = make(map[string]struct{}, len(methods))
+		}
+		for _, m := range methods {
+			o.signatureExemptMethods[m] = struct{}{}
+		}
+	}
+}
+
+// WithSyncOnError 设置服务端 trace 拦截器记录失败日志后是否调用 logger.Sync()，默认关闭
+// 开启后失败日志在 RPC 返回前写出，进程随后崩溃也不会丢失，代价是失败请求增加一次刷盘的延迟
+func WithSyncOnError(enabled bool) Option {
+	return func(o *options) {
+		o.syncOnError = enabled
+	}
+}
+
+// WithSplitMethodLabels 设置 metrics 拦截器是否将 /pkg.Service/Method 拆分为 service 和 method 两个标签记录，默认关闭
+// 便于在看板中按服务聚合；无法解析的方法名 service 记为 unknown，method 保留原始字符串。
+// 开启后指标记录到 grpc_service_requests_total、grpc_service_request_duration_seconds 等 grpc_service_ 前缀的指标，
+// 不与 method 标签为完整方法名的 grpc_requests_total 等指标混用
+func WithSplitMethodLabels(enabled bool) Option {
+	return func(o *options) {
+		o.splitMethodLabels = enabled
+	}
+}
+
+// WithMetricsRegistry 设置 MetricsUnaryInterceptor 注册指标的 registry，默认使用 framework-metrics 所在的默认 registry
+// 与 MetricsUnaryInterceptorWithRegistry 的 reg 参数作用相同，便于通过 DefaultUnaryServerInterceptors 等只接收选项的构造函数
+// 使用 WithCallerLabel、WithConstLabels 等改变指标标签集合的选项
+func WithMetricsRegistry(reg prometheus.Registerer) Option {
+	return func(o *options) {
+		o.metricsRegistry = reg
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// otelGRPCMetrics 基于 OpenTelemetry metrics 的指标集合
+type otelGRPCMetrics struct {
+	requestTotal    metric.Int64Counter
+	requestDuration metric.Float64Histogram
+}
+
+// MetricsUnaryInterceptorOTel 创建基于 OpenTelemetry metrics 的 gRPC metrics 拦截器
+// 与 MetricsUnaryInterceptor 记录相同的请求总数和耗时，属性为 rpc.method 和 rpc.grpc.status_code，
+// 适用于统一使用 OTel metrics SDK、不直接依赖 Prometheus 的服务
+func MetricsUnaryInterceptorOTel(meter metric.Meter, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+
+	requestTotal, err := meter.Int64Counter("rpc.server.requests",
+		metric.WithDescription("Total number of gRPC requests"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		log.Warn("Failed to create gRPC request counter", zap.Error(err))
+	}
+	requestDuration, err := meter.Float64Histogram("rpc.server.duration",
+		metric.WithDescription("gRPC request duration in seconds"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		log.Warn("Failed to create gRPC request duration histogram", zap.Error(err))
+	}
+
+	m := &otelGRPCMetrics{
+		requestTotal:    requestTotal,
+		requestDuration: requestDuration,
+	}
+	return metricsUnaryInterceptor(o, m.record)
+}
+
+// record 将单次请求的结果写入 OpenTelemetry 指标
+func (m *otelGRPCMetrics) record(ctx context.Context, method string, code codes.Code, elapsed time.Duration) {
+	attrs := metric.WithAttributes(
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.grpc.status_code", code.String()),
+	)
+
+	if m.requestTotal != nil {
+		m.requestTotal.Add(ctx, 1, attrs)
+	}
+	if m.requestDuration != nil {
+		m.requestDuration.Record(ctx, elapsed.Seconds(), attrs)
+	}
+}
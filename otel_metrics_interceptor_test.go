@@ -0,0 +1,116 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeMeter 记录计数器和直方图调用的 metric.Meter 实现
+type fakeMeter struct {
+	noop.Meter
+	counter   *fakeCounter
+	histogram *fakeHistogram
+}
+
+func newFakeMeter() *fakeMeter {
+	return &fakeMeter{
+		counter:   &fakeCounter{},
+		histogram: &fakeHistogram{},
+	}
+}
+
+func (m *fakeMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return m.counter, nil
+}
+
+func (m *fakeMeter) Float64Histogram(name string, opts ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return m.histogram, nil
+}
+
+type fakeCounter struct {
+	noop.Int64Counter
+	total int64
+	attrs attribute.Set
+}
+
+func (c *fakeCounter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	c.total += incr
+	c.attrs = metric.NewAddConfig(opts).Attributes()
+}
+
+type fakeHistogram struct {
+	noop.Float64Histogram
+	count int
+}
+
+func (h *fakeHistogram) Record(ctx context.Context, value float64, opts ...metric.RecordOption) {
+	h.count++
+}
+
+func TestMetricsUnaryInterceptorOTel(t *testing.T) {
+	meter := newFakeMeter()
+	interceptor := MetricsUnaryInterceptorOTel(meter)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("interceptor() returned %v, want NotFound", err)
+	}
+
+	if meter.counter.total != 1 {
+		t.Errorf("request counter = %d, want 1", meter.counter.total)
+	}
+	if meter.histogram.count != 1 {
+		t.Errorf("duration histogram recorded %d times, want 1", meter.histogram.count)
+	}
+	if v, _ := meter.counter.attrs.Value("rpc.method"); v.AsString() != info.FullMethod {
+		t.Errorf("rpc.method = %q, want %q", v.AsString(), info.FullMethod)
+	}
+	if v, _ := meter.counter.attrs.Value("rpc.grpc.status_code"); v.AsString() != codes.NotFound.String() {
+		t.Errorf("rpc.grpc.status_code = %q, want %q", v.AsString(), codes.NotFound.String())
+	}
+}
+
+func TestMetricsUnaryInterceptorOTel_PassesThroughError(t *testing.T) {
+	interceptor := MetricsUnaryInterceptorOTel(noop.NewMeterProvider().Meter("test"))
+
+	expectedErr := errors.New("boom")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, expectedErr
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); !errors.Is(err, expectedErr) {
+		t.Errorf("interceptor() returned error %v, want %v", err, expectedErr)
+	}
+}
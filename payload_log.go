@@ -0,0 +1,78 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// PayloadEncoder 将请求、响应消息编码为日志中记录的内容
+type PayloadEncoder func(msg proto.Message) ([]byte, error)
+
+// defaultPayloadEncoder 默认的 payload 编码器，使用 protojson 完整输出消息
+func defaultPayloadEncoder(msg proto.Message) ([]byte, error) {
+	return protojson.Marshal(msg)
+}
+
+// CompactPayloadEncoder 紧凑的 payload 编码器：使用 proto 字段名并去除所有多余空白，便于 grep
+func CompactPayloadEncoder(msg proto.Message) ([]byte, error) {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// appendPayloadFields 追加请求内容，请求成功时同时追加响应内容
+func appendPayloadFields(fields []zap.Field, req, resp interface{}, err error, encode PayloadEncoder) []zap.Field {
+	if f, ok := payloadField("request", req, encode); ok {
+		fields = append(fields, f)
+	}
+	if err == nil {
+		if f, ok := payloadField("response", resp, encode); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// payloadField 将消息编码为日志字段，非 proto 消息或编码失败时返回 false
+func payloadField(key string, v interface{}, encode PayloadEncoder) (zap.Field, bool) {
+	msg, ok := v.(proto.Message)
+	if !ok || msg == nil {
+		return zap.Field{}, false
+	}
+	if encode == nil {
+		encode = defaultPayloadEncoder
+	}
+
+	b, err := encode(msg)
+	if err != nil {
+		return zap.Field{}, false
+	}
+	return zap.String(key, string(b)), true
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCompactPayloadEncoder(t *testing.T) {
+	msg := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("order_id"),
+		Number:   proto.Int32(7),
+		JsonName: proto.String("orderId"),
+	}
+
+	b, err := CompactPayloadEncoder(msg)
+	if err != nil {
+		t.Fatalf("CompactPayloadEncoder() error = %v", err)
+	}
+
+	got := string(b)
+	if strings.ContainsAny(got, " \n") {
+		t.Errorf("CompactPayloadEncoder() = %q, want no whitespace", got)
+	}
+	if !strings.Contains(got, `"json_name":"orderId"`) {
+		t.Errorf("CompactPayloadEncoder() = %q, want proto field names", got)
+	}
+}
+
+func TestTraceUnaryInterceptor_PayloadLogging(t *testing.T) {
+	req := wrapperspb.String("ping")
+	resp := wrapperspb.String("pong")
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return resp, nil
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		readLogs := captureLogs(t)
+
+		if _, err := TraceUnaryInterceptor()(context.Background(), req, info, handler); err != nil {
+			t.Fatalf("interceptor() returned unexpected error: %v", err)
+		}
+
+		entry := findLog(readLogs(), "gRPC request completed")
+		if entry == nil {
+			t.Fatal("completion log not found")
+		}
+		if _, ok := entry["request"]; ok {
+			t.Errorf("completion log request = %v, want absent", entry["request"])
+		}
+	})
+
+	t.Run("custom encoder", func(t *testing.T) {
+		readLogs := captureLogs(t)
+
+		interceptor := TraceUnaryInterceptor(
+			WithPayloadLogging(true),
+			WithPayloadLogEncoder(CompactPayloadEncoder),
+		)
+		if _, err := interceptor(context.Background(), req, info, handler); err != nil {
+			t.Fatalf("interceptor() returned unexpected error: %v", err)
+		}
+
+		entry := findLog(readLogs(), "gRPC request completed")
+		if entry == nil {
+			t.Fatal("completion log not found")
+		}
+		if entry["request"] != `"ping"` {
+			t.Errorf("completion log request = %v, want %q", entry["request"], `"ping"`)
+		}
+		if entry["response"] != `"pong"` {
+			t.Errorf("completion log response = %v, want %q", entry["response"], `"pong"`)
+		}
+	})
+}
+
+func TestTraceUnaryInterceptorWithControl_PayloadLogging(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	req := wrapperspb.String("ping")
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return wrapperspb.String("pong"), nil
+	}
+
+	interceptor, ctl := TraceUnaryInterceptorWithControl()
+	if ctl.PayloadLogging() {
+		t.Fatal("PayloadLogging() = true, want false by default")
+	}
+
+	for _, enabled := range []bool{false, true, false} {
+		ctl.SetPayloadLogging(enabled)
+		if _, err := interceptor(context.Background(), req, info, handler); err != nil {
+			t.Fatalf("interceptor() returned unexpected error: %v", err)
+		}
+	}
+
+	var logged []bool
+	for _, entry := range readLogs() {
+		if entry["msg"] == "gRPC request completed" {
+			_, ok := entry["request"]
+			logged = append(logged, ok)
+		}
+	}
+	if want := []bool{false, true, false}; len(logged) != len(want) || logged[0] != want[0] || logged[1] != want[1] || logged[2] != want[2] {
+		t.Errorf("payload logged per request = %v, want %v", logged, want)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PriorityUnaryInterceptor 创建按优先级丢弃请求的拦截器，负载过高时优先丢弃低优先级流量
+// priorityFn 返回请求的优先级（数值越大越重要），shedBelow 返回当前的丢弃阈值，可由调用方根据负载信号动态调整。
+// 请求优先级低于阈值时返回 ResourceExhausted，并按优先级计入 GRPCShedTotal 和 GRPCRequestRejectedTotal
+func PriorityUnaryInterceptor(priorityFn func(ctx context.Context, fullMethod string) int, shedBelow func() int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := fullMethodFromInfo(info)
+		priority := priorityFn(ctx, method)
+		if threshold := shedBelow(); priority < threshold {
+			GRPCShedTotal.WithLabelValues(strconv.Itoa(priority)).Inc()
+			GRPCRequestRejectedTotal.WithLabelValues(method, "priority_shed").Inc()
+			return nil, status.Errorf(codes.ResourceExhausted, "request shed: priority %d below threshold %d", priority, threshold)
+		}
+		return handler(ctx, req)
+	}
+}
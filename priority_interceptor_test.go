@@ -0,0 +1,66 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPriorityUnaryInterceptor(t *testing.T) {
+	priorities := map[string]int{
+		"/test.Service/Batch":    1,
+		"/test.Service/Checkout": 10,
+	}
+	var threshold int32
+	interceptor := PriorityUnaryInterceptor(
+		func(ctx context.Context, fullMethod string) int { return priorities[fullMethod] },
+		func() int { return int(atomic.LoadInt32(&threshold)) },
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	call := func(method string) error {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	shed := GRPCShedTotal.WithLabelValues("1")
+	before := testutil.ToFloat64(shed)
+
+	// 负载正常时不丢弃任何请求
+	if err := call("/test.Service/Batch"); err != nil {
+		t.Errorf("low priority under normal load error = %v", err)
+	}
+
+	// 负载升高后只丢弃优先级低于阈值的请求
+	atomic.StoreInt32(&threshold, 5)
+	if got := status.Code(call("/test.Service/Batch")); got != codes.ResourceExhausted {
+		t.Errorf("low priority under load code = %v, want %v", got, codes.ResourceExhausted)
+	}
+	if err := call("/test.Service/Checkout"); err != nil {
+		t.Errorf("high priority under load error = %v", err)
+	}
+
+	if got := testutil.ToFloat64(shed) - before; got != 1 {
+		t.Errorf("shed counter increment = %v, want 1", got)
+	}
+}
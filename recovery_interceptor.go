@@ -0,0 +1,90 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/go-anyway/framework-log"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnaryInterceptor 创建 panic 恢复拦截器
+// 处理器 panic 时记录包含堆栈、traceID 和 requestID 的错误日志，并返回 Internal，避免单个请求导致进程崩溃；
+// panic 的值实现了 GRPCStatus() *status.Status 时返回该状态。
+// 应放在 TraceUnaryInterceptor 之后，使日志带有关联 ID
+func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if handler == nil {
+			return nil, errNilHandler
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, panicToError(ctx, fullMethodFromInfo(info), r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamInterceptor 创建流请求的 panic 恢复拦截器，行为与 RecoveryUnaryInterceptor 相同
+// 日志使用流的 context，前面的拦截器通过包装流放入的 traceID、requestID 同样会被记录
+func RecoveryStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panicToError(ss.Context(), fullMethodFromStreamInfo(info), r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// grpcStatusError 携带 gRPC 状态的错误，例如处理器自定义的 *AppError
+type grpcStatusError interface {
+	GRPCStatus() *status.Status
+}
+
+// panicToError 记录 panic 日志并将其转换为返回给客户端的错误
+// panic 的值实现了 GRPCStatus() *status.Status 时直接返回该状态，视为处理器有意的控制流，只记录警告；
+// 其余值记录包含堆栈的错误日志并返回 Internal，错误信息中不包含 panic 的具体内容
+func panicToError(ctx context.Context, method string, r interface{}) error {
+	if se, ok := r.(grpcStatusError); ok {
+		if st := se.GRPCStatus(); st != nil && st.Code() != codes.OK {
+			log.FromContext(ctx).Warn("gRPC handler panicked with status",
+				zap.String("method", method),
+				zap.String("code", st.Code().String()),
+				zap.String("panic", fmt.Sprint(r)),
+			)
+			return st.Err()
+		}
+	}
+
+	log.FromContext(ctx).Error("gRPC handler panicked",
+		zap.String("method", method),
+		zap.String("panic", fmt.Sprint(r)),
+		zap.ByteString("stack", debug.Stack()),
+	)
+	return status.Error(codes.Internal, "internal server error")
+}
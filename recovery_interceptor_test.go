@@ -0,0 +1,116 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Panic",
+	}
+	ctx := log.ContextWithRequestID(context.Background(), "req-1")
+	_, err := RecoveryUnaryInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if got := status.Code(err); got != codes.Internal {
+		t.Fatalf("code = %v, want %v", got, codes.Internal)
+	}
+
+	entry := findLog(readLogs(), "gRPC handler panicked")
+	if entry == nil {
+		t.Fatal("panic log not found")
+	}
+	if entry["panic"] != "boom" || entry["method"] != info.FullMethod {
+		t.Errorf("panic log = %v, want panic=boom method=%s", entry, info.FullMethod)
+	}
+	if stack, _ := entry["stack"].(string); !strings.Contains(stack, "TestRecoveryUnaryInterceptor") {
+		t.Errorf("stack does not contain the panicking test function: %q", stack)
+	}
+}
+
+func TestRecoveryStreamInterceptor(t *testing.T) {
+	readLogs := captureLogs(t)
+
+	info := &grpc.StreamServerInfo{
+		FullMethod: "/test.Service/StreamPanic",
+	}
+	ss := newFakeServerStream(log.ContextWithRequestID(context.Background(), "req-2"))
+	err := RecoveryStreamInterceptor()(nil, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+		panic("stream boom")
+	})
+	if got := status.Code(err); got != codes.Internal {
+		t.Fatalf("code = %v, want %v", got, codes.Internal)
+	}
+
+	entry := findLog(readLogs(), "gRPC handler panicked")
+	if entry == nil {
+		t.Fatal("panic log not found")
+	}
+	if entry["requestID"] != "req-2" {
+		t.Errorf("requestID = %v, want req-2", entry["requestID"])
+	}
+}
+
+// appError 携带 gRPC 状态码的业务错误
+type appError struct {
+	code codes.Code
+	msg  string
+}
+
+func (e *appError) Error() string { return e.msg }
+
+func (e *appError) GRPCStatus() *status.Status { return status.New(e.code, e.msg) }
+
+func TestRecoveryUnaryInterceptor_TypedPanic(t *testing.T) {
+	captureLogs(t)
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TypedPanic",
+	}
+	tests := []struct {
+		name     string
+		value    interface{}
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{name: "app error", value: &appError{code: codes.NotFound, msg: "user not found"}, wantCode: codes.NotFound, wantMsg: "user not found"},
+		{name: "status error", value: status.Error(codes.PermissionDenied, "denied"), wantCode: codes.PermissionDenied, wantMsg: "denied"},
+		{name: "arbitrary value", value: 42, wantCode: codes.Internal, wantMsg: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RecoveryUnaryInterceptor()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				panic(tt.value)
+			})
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg {
+				t.Errorf("status = %v %q, want %v %q", st.Code(), st.Message(), tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
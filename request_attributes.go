@@ -0,0 +1,74 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requestFieldAttributes 通过 proto 反射读取请求中的顶层标量字段，并转换为 span 属性
+// fieldToAttr 的 key 为 proto 字段名，value 为属性名；嵌套消息、repeated 和 map 字段会被跳过
+func requestFieldAttributes(req interface{}, fieldToAttr map[string]string) []attribute.KeyValue {
+	msg, ok := req.(proto.Message)
+	if !ok || len(fieldToAttr) == 0 {
+		return nil
+	}
+
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	attrs := make([]attribute.KeyValue, 0, len(fieldToAttr))
+	for field, key := range fieldToAttr {
+		fd := fields.ByName(protoreflect.Name(field))
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			continue
+		}
+		if kv, ok := scalarAttribute(attribute.Key(key), fd, m.Get(fd)); ok {
+			attrs = append(attrs, kv)
+		}
+	}
+	return attrs
+}
+
+// scalarAttribute 将标量字段的值转换为 span 属性，非标量字段返回 false
+func scalarAttribute(key attribute.Key, fd protoreflect.FieldDescriptor, v protoreflect.Value) (attribute.KeyValue, bool) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return key.Bool(v.Bool()), true
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return key.Int64(v.Int()), true
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return key.Int64(int64(v.Uint())), true
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// uint64 可能超出 int64 范围，使用字符串表示
+		return key.String(v.String()), true
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return key.Float64(v.Float()), true
+	case protoreflect.StringKind:
+		return key.String(v.String()), true
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return key.String(string(ev.Name())), true
+		}
+		return key.Int64(int64(v.Enum())), true
+	default:
+		// bytes、message、group 不作为属性
+		return attribute.KeyValue{}, false
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestRequestFieldAttributes(t *testing.T) {
+	req := &descriptorpb.FieldDescriptorProto{
+		Name:    proto.String("order_id"),
+		Number:  proto.Int32(7),
+		Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Options: &descriptorpb.FieldOptions{},
+	}
+
+	attrs := requestFieldAttributes(req, map[string]string{
+		"name":    "app.name",
+		"number":  "app.number",
+		"label":   "app.label",
+		"options": "app.options",
+		"missing": "app.missing",
+	})
+
+	got := make(map[attribute.Key]attribute.Value)
+	for _, kv := range attrs {
+		got[kv.Key] = kv.Value
+	}
+
+	if v := got["app.name"]; v.AsString() != "order_id" {
+		t.Errorf("app.name = %q, want %q", v.AsString(), "order_id")
+	}
+	if v := got["app.number"]; v.AsInt64() != 7 {
+		t.Errorf("app.number = %d, want 7", v.AsInt64())
+	}
+	if v := got["app.label"]; v.AsString() != "LABEL_OPTIONAL" {
+		t.Errorf("app.label = %q, want %q", v.AsString(), "LABEL_OPTIONAL")
+	}
+	if _, ok := got["app.options"]; ok {
+		t.Error("nested message field should be skipped")
+	}
+	if _, ok := got["app.missing"]; ok {
+		t.Error("unknown field should be skipped")
+	}
+}
+
+func TestRequestFieldAttributes_SkipsRepeatedAndNonProto(t *testing.T) {
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"a", "b"}}
+	if attrs := requestFieldAttributes(mask, map[string]string{"paths": "app.paths"}); len(attrs) != 0 {
+		t.Errorf("repeated field produced attributes %v, want none", attrs)
+	}
+	if attrs := requestFieldAttributes("not a proto", map[string]string{"name": "app.name"}); len(attrs) != 0 {
+		t.Errorf("non-proto request produced attributes %v, want none", attrs)
+	}
+}
+
+func TestTraceUnaryInterceptor_WithRequestFieldAttributes(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	interceptor := TraceUnaryInterceptor(WithRequestFieldAttributes(map[string]string{"name": "app.name"}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	req := &descriptorpb.FieldDescriptorProto{Name: proto.String("order-42")}
+	if _, err := interceptor(context.Background(), req, info, handler); err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])["app.name"].AsString(); got != "order-42" {
+		t.Errorf("app.name = %q, want %q", got, "order-42")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// RequestIDFormat 拦截器生成的 requestID 格式
+type RequestIDFormat int
+
+const (
+	// RequestIDHex 32 位十六进制字符串（默认）
+	RequestIDHex RequestIDFormat = iota + 1
+	// RequestIDUUID 带连字符的 UUID v4 格式，例如 9f1c2d3e-4b5a-4c6d-8e7f-0a1b2c3d4e5f
+	RequestIDUUID
+	// RequestIDBase62 22 位 base62 字符串，较短且只包含字母和数字
+	RequestIDBase62
+)
+
+// base62Alphabet base62 编码使用的字符表
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// base62Length 128 位随机数编码为 base62 后的固定长度
+const base62Length = 22
+
+// generateRequestID 按 format 生成 128 位随机 requestID，format 无效时使用十六进制格式
+func generateRequestID(format RequestIDFormat) string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// 如果随机数生成失败，使用时间戳作为后备方案
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	switch format {
+	case RequestIDUUID:
+		// 按 RFC 4122 设置版本号（4）和变体位
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case RequestIDBase62:
+		return encodeBase62(b)
+	default:
+		return hex.EncodeToString(b)
+	}
+}
+
+// encodeBase62 将 b 按大端整数编码为定长 base62 字符串，不足 base62Length 位时左侧补 0
+func encodeBase62(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(int64(len(base62Alphabet)))
+	mod := new(big.Int)
+
+	out := make([]byte, base62Length)
+	for i := base62Length - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+	return string(out)
+}
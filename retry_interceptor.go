@@ -0,0 +1,177 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// attemptHeader 客户端标记当前尝试次数的 metadata key
+const attemptHeader = "x-attempt"
+
+// attemptFromMetadata 返回 x-attempt 标记的尝试次数，缺失或无效时返回 1
+func attemptFromMetadata(md metadata.MD) int {
+	value := lookupMetadata(md, attemptHeader)
+	if value == "" {
+		return 1
+	}
+	attempt, err := strconv.Atoi(value)
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return attempt
+}
+
+// RetryUnaryClientInterceptor 创建 gRPC 客户端重试拦截器
+// 对 WithRetryCodes 指定的状态码（默认 Unavailable）按指数退避重试，最多尝试 WithMaxAttempts 次。
+// 每次尝试都会创建一个独立的子 span 并设置 rpc.grpc.attempt 属性（从 1 开始），
+// 同时通过 x-attempt metadata 告知服务端当前尝试次数。
+// 与 TraceUnaryClientInterceptor 组合时应将 trace 拦截器放在外层，使各次尝试挂在同一个调用 span 下
+func RetryUnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts...)
+	budget := newRetryBudget(o.retryBudgetRatio, o.retryBudgetMinPerSec)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = o.enterChain(ctx)
+		var err error
+		backoff := o.retryBackoff
+		for attempt := 1; attempt <= o.maxAttempts; attempt++ {
+			err = invokeAttempt(ctx, o, attempt, method, req, reply, cc, invoker, opts...)
+			if err == nil {
+				budget.deposit()
+				return nil
+			}
+			if !o.retryable(status.Code(err)) || attempt == o.maxAttempts {
+				return err
+			}
+			if !budget.withdraw() {
+				GRPCRetryBudgetExhaustedTotal.WithLabelValues(method).Inc()
+				return err
+			}
+
+			// 等待退避时间，调用方取消时直接返回最后一次错误
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+		return err
+	}
+}
+
+// retryBudgetMaxTokens 重试预算最多累积的令牌数，避免长时间无故障后积累过多重试额度
+const retryBudgetMaxTokens = 100
+
+// retryBudget 客户端重试的令牌桶预算，为 nil 时不限制重试
+type retryBudget struct {
+	mu        sync.Mutex
+	ratio     float64
+	minPerSec int
+	tokens    float64
+	// second 当前秒的起始时间，reserveUsed 为当前秒已使用的保底重试次数
+	second      time.Time
+	reserveUsed int
+}
+
+// newRetryBudget 创建重试预算，ratio 和 minPerSec 都为 0 时返回 nil 表示不限制
+func newRetryBudget(ratio float64, minPerSec int) *retryBudget {
+	if ratio == 0 && minPerSec == 0 {
+		return nil
+	}
+	return &retryBudget{
+		ratio:     ratio,
+		minPerSec: minPerSec,
+	}
+}
+
+// deposit 成功调用后存入令牌
+func (b *retryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.tokens+b.ratio, retryBudgetMaxTokens)
+}
+
+// withdraw 尝试为一次重试扣除额度，优先使用每秒的保底次数
+func (b *retryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := nowFunc()
+	if now.Sub(b.second) >= time.Second {
+		b.second = now
+		b.reserveUsed = 0
+	}
+	if b.reserveUsed < b.minPerSec {
+		b.reserveUsed++
+		return true
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	return false
+}
+
+// invokeAttempt 以独立子 span 执行一次调用尝试
+func invokeAttempt(ctx context.Context, o *options, attempt int, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := o.startSpan(ctx, o.spanName(method))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("rpc.method", method),
+		attribute.Int("rpc.grpc.attempt", attempt),
+	)
+
+	// 标记尝试次数，并将追踪上下文更新为本次尝试的 span，使服务端 span 挂在对应的尝试下
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	md.Set(attemptHeader, strconv.Itoa(attempt))
+	injectTraceContext(ctx, md)
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	span.SetAttributes(attribute.String("rpc.status_code", statusCodeString(err)))
+	o.setNumericStatus(span, err)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryUnaryClientInterceptor_AttemptSpans(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	traceInterceptor := TraceUnaryClientInterceptor()
+	retryInterceptor := RetryUnaryClientInterceptor(WithRetryBackoff(time.Millisecond))
+
+	var attempts []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		attempts = append(attempts, lookupMetadata(md, "x-attempt"))
+		if len(attempts) < 3 {
+			return status.Error(codes.Unavailable, "unavailable")
+		}
+		return nil
+	}
+
+	// trace 在外层，retry 在内层
+	err := traceInterceptor(context.Background(), "/test.Service/TestMethod", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return retryInterceptor(ctx, method, req, reply, cc, invoker, opts...)
+		})
+	if err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	wantAttempts := []string{"1", "2", "3"}
+	if len(attempts) != len(wantAttempts) {
+		t.Fatalf("invoker called %d times, want %d", len(attempts), len(wantAttempts))
+	}
+	for i, want := range wantAttempts {
+		if attempts[i] != want {
+			t.Errorf("attempt %d x-attempt = %q, want %q", i+1, attempts[i], want)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("recorded %d spans, want 4 (1 call + 3 attempts)", len(spans))
+	}
+
+	parent := spans[len(spans)-1]
+	for i, span := range spans[:3] {
+		if got := spanAttributes(span)["rpc.grpc.attempt"].AsInt64(); got != int64(i+1) {
+			t.Errorf("span %d rpc.grpc.attempt = %d, want %d", i, got, i+1)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("attempt span %d is not a child of the call span", i)
+		}
+	}
+}
+
+func TestRetryUnaryClientInterceptor_NonRetryableCode(t *testing.T) {
+	interceptor := RetryUnaryClientInterceptor(WithRetryBackoff(time.Millisecond))
+
+	var calls int
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.InvalidArgument, "bad request")
+	}
+
+	err := interceptor(context.Background(), "/test.Service/TestMethod", nil, nil, nil, invoker)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("interceptor() returned %v, want InvalidArgument", err)
+	}
+	if calls != 1 {
+		t.Errorf("invoker called %d times, want 1", calls)
+	}
+}
+
+func TestRetryUnaryClientInterceptor_MaxAttempts(t *testing.T) {
+	interceptor := RetryUnaryClientInterceptor(WithMaxAttempts(2), WithRetryBackoff(time.Millisecond))
+
+	var calls int
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+
+	err := interceptor(context.Background(), "/test.Service/TestMethod", nil, nil, nil, invoker)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("interceptor() returned %v, want Unavailable", err)
+	}
+	if calls != 2 {
+		t.Errorf("invoker called %d times, want 2", calls)
+	}
+}
+
+func TestRetryUnaryClientInterceptor_WithRetryBudget(t *testing.T) {
+	now := time.Unix(0, 0)
+	setNowFunc(t, func() time.Time { return now })
+
+	const failMethod = "/test.Service/Budgeted"
+	counter := GRPCRetryBudgetExhaustedTotal.WithLabelValues(failMethod)
+	before := testutil.ToFloat64(counter)
+
+	var attempts int
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if method != failMethod {
+			return nil
+		}
+		attempts++
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+	call := func(interceptor grpc.UnaryClientInterceptor, method string) int {
+		attempts = 0
+		_ = interceptor(context.Background(), method, nil, nil, nil, invoker)
+		return attempts
+	}
+
+	t.Run("ratio of successful calls", func(t *testing.T) {
+		interceptor := RetryUnaryClientInterceptor(WithRetryBackoff(0), WithRetryBudget(0.5, 0))
+
+		// 还没有成功调用，预算为空，不重试
+		if got := call(interceptor, failMethod); got != 1 {
+			t.Errorf("attempts with empty budget = %d, want 1", got)
+		}
+
+		// 两次成功调用存入 1 个令牌，只够一次重试
+		call(interceptor, "/test.Service/OK")
+		call(interceptor, "/test.Service/OK")
+		if got := call(interceptor, failMethod); got != 2 {
+			t.Errorf("attempts with one token = %d, want 2", got)
+		}
+	})
+
+	t.Run("min retries per second", func(t *testing.T) {
+		interceptor := RetryUnaryClientInterceptor(WithRetryBackoff(0), WithRetryBudget(0, 1))
+
+		if got := call(interceptor, failMethod); got != 2 {
+			t.Errorf("attempts in first second = %d, want 2", got)
+		}
+		if got := call(interceptor, failMethod); got != 1 {
+			t.Errorf("attempts after reserve is used = %d, want 1", got)
+		}
+		now = now.Add(time.Second)
+		if got := call(interceptor, failMethod); got != 2 {
+			t.Errorf("attempts in next second = %d, want 2", got)
+		}
+	})
+
+	if got := testutil.ToFloat64(counter) - before; got != 5 {
+		t.Errorf("budget exhausted counter increment = %v, want 5", got)
+	}
+}
+
+func TestTraceUnaryInterceptor_AttemptFromMetadata(t *testing.T) {
+	recorder := setupTestTracer(t)
+	readLogs := captureLogs(t)
+
+	interceptor := TraceUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	for _, md := range []metadata.MD{
+		metadata.Pairs("x-request-id", "req-1", "x-attempt", "3"),
+		metadata.Pairs("x-request-id", "req-2"),
+		metadata.Pairs("x-request-id", "req-3", "x-attempt", "bogus"),
+	} {
+		if _, err := interceptor(metadata.NewIncomingContext(context.Background(), md), nil, info, handler); err != nil {
+			t.Fatalf("interceptor() error = %v", err)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("spans = %d, want 3", len(spans))
+	}
+	for i, want := range []int64{3, 1, 1} {
+		if got := spanAttributes(spans[i])["rpc.grpc.attempt"].AsInt64(); got != want {
+			t.Errorf("span %d rpc.grpc.attempt = %d, want %d", i, got, want)
+		}
+	}
+
+	entry := findLog(readLogs(), "gRPC request started")
+	if entry == nil {
+		t.Fatal("start log not found")
+	}
+	if entry["attempt"] != float64(3) {
+		t.Errorf("attempt = %v, want 3", entry["attempt"])
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// SchemaVersionUnaryInterceptor 创建 schema 版本兼容性校验拦截器
+// 从 header（例如 x-schema-version）中读取客户端的整数 schema 版本，低于 minVersion 时返回 FailedPrecondition，
+// 版本无法解析时返回 InvalidArgument，用于 proto 变更迁移期间集中拒绝不兼容的客户端。
+// 缺少版本时默认放行，可通过 WithRequireSchemaVersion(true) 改为返回 FailedPrecondition
+func SchemaVersionUnaryInterceptor(minVersion int, header string, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = o.enterChain(ctx)
+		md, _ := metadata.FromIncomingContext(ctx)
+		value := strings.TrimSpace(lookupMetadata(md, header))
+		if value == "" {
+			if o.requireSchemaVersion {
+				GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "missing_schema_version").Inc()
+				return nil, status.Errorf(codes.FailedPrecondition, "missing schema version metadata: %s", header)
+			}
+			return handler(ctx, req)
+		}
+
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "invalid_schema_version").Inc()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid schema version %q", value)
+		}
+		if version < minVersion {
+			GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "schema_version_too_old").Inc()
+			return nil, status.Errorf(codes.FailedPrecondition, "schema version %d is below minimum supported version %d", version, minVersion)
+		}
+
+		return handler(ctx, req)
+	}
+}
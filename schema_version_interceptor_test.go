@@ -0,0 +1,107 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestSchemaVersionUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/SchemaVersion",
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		md         metadata.MD
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{
+			name:     "version above minimum",
+			md:       metadata.Pairs("x-schema-version", "4"),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "version equal to minimum",
+			md:       metadata.Pairs("x-schema-version", "3"),
+			wantCode: codes.OK,
+		},
+		{
+			name:       "version below minimum",
+			md:         metadata.Pairs("x-schema-version", "2"),
+			wantCode:   codes.FailedPrecondition,
+			wantReason: "schema_version_too_old",
+		},
+		{
+			name:       "invalid version",
+			md:         metadata.Pairs("x-schema-version", "v3"),
+			wantCode:   codes.InvalidArgument,
+			wantReason: "invalid_schema_version",
+		},
+		{
+			name:     "missing version passes through by default",
+			md:       metadata.MD{},
+			wantCode: codes.OK,
+		},
+		{
+			name:       "missing version rejected when required",
+			opts:       []Option{WithRequireSchemaVersion(true)},
+			md:         metadata.MD{},
+			wantCode:   codes.FailedPrecondition,
+			wantReason: "missing_schema_version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := SchemaVersionUnaryInterceptor(3, "X-Schema-Version", tt.opts...)
+
+			var before float64
+			if tt.wantReason != "" {
+				before = testutil.ToFloat64(GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, tt.wantReason))
+			}
+
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "response", nil
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := interceptor(ctx, nil, info, handler)
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			if called != (tt.wantCode == codes.OK) {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCode == codes.OK)
+			}
+			if tt.wantReason != "" {
+				if got := testutil.ToFloat64(GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, tt.wantReason)) - before; got != 1 {
+					t.Errorf("rejected counter increment = %v, want 1", got)
+				}
+			}
+		})
+	}
+}
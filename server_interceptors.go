@@ -0,0 +1,73 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultUnaryServerInterceptors 返回推荐顺序的服务端一元拦截器链：trace（最外层）、metrics
+// 设置 WithChainName 时各拦截器分别命名为 <name>.trace、<name>.metrics；
+// 使用 WithCallerLabel、WithConstLabels 等改变指标标签集合的选项时需要同时通过 WithMetricsRegistry 指定 registry
+// 启用 WithInterceptorTiming 时每个拦截器自身的耗时会按名称（trace、metrics）记录到 GRPCInterceptorDuration
+func DefaultUnaryServerInterceptors(opts ...Option) []grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+
+	chain := []struct {
+		name        string
+		interceptor grpc.UnaryServerInterceptor
+	}{
+		{name: "trace", interceptor: TraceUnaryInterceptor(chainMemberOptions(opts, "trace")...)},
+		{name: "metrics", interceptor: MetricsUnaryInterceptor(chainMemberOptions(opts, "metrics")...)},
+	}
+
+	interceptors := make([]grpc.UnaryServerInterceptor, 0, len(chain))
+	for _, c := range chain {
+		if o.interceptorTiming {
+			interceptors = append(interceptors, timedUnaryInterceptor(c.name, c.interceptor))
+		} else {
+			interceptors = append(interceptors, c.interceptor)
+		}
+	}
+	return interceptors
+}
+
+// timedUnaryInterceptor 包装拦截器，记录其自身的耗时：总耗时减去调用后续拦截器和处理器的耗时
+func timedUnaryInterceptor(name string, next grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	observer := GRPCInterceptorDuration.WithLabelValues(name)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var inner time.Duration
+		timedHandler := handler
+		if handler != nil {
+			timedHandler = func(ctx context.Context, req interface{}) (interface{}, error) {
+				start := nowFunc()
+				resp, err := handler(ctx, req)
+				inner += since(start)
+				return resp, err
+			}
+		}
+
+		start := nowFunc()
+		resp, err := next(ctx, req, info, timedHandler)
+		observer.Observe((since(start) - inner).Seconds())
+		return resp, err
+	}
+}
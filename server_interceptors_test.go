@@ -0,0 +1,152 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTimedUnaryInterceptor(t *testing.T) {
+	GRPCInterceptorDuration.Reset()
+	t.Cleanup(GRPCInterceptorDuration.Reset)
+
+	clock := &fakeClock{now: time.Unix(0, 0), step: 10 * time.Millisecond}
+	setNowFunc(t, clock.Now)
+
+	// 拦截器自身在调用处理器前后各消耗一次时钟步长
+	passthrough := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		nowFunc()
+		resp, err := handler(ctx, req)
+		nowFunc()
+		return resp, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		// 处理器耗时 50ms，不应计入拦截器
+		clock.now = clock.now.Add(50 * time.Millisecond)
+		return "ok", nil
+	}
+
+	interceptor := timedUnaryInterceptor("passthrough", passthrough)
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Timed"}, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	// 时钟读数：外层开始 0、拦截器 10、处理器开始 20、处理器结束 80、拦截器 90、外层结束 100
+	// 拦截器自身耗时 = 100 - (80 - 20) = 40ms
+	want := `
+# HELP grpc_interceptor_duration_seconds Time spent in each gRPC server interceptor itself, excluding the rest of the chain, in seconds
+# TYPE grpc_interceptor_duration_seconds histogram
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="1e-05"} 0
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="5e-05"} 0
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="0.0001"} 0
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="0.0005"} 0
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="0.001"} 0
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="0.005"} 0
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="0.01"} 0
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="0.05"} 1
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="0.1"} 1
+grpc_interceptor_duration_seconds_bucket{interceptor="passthrough",le="+Inf"} 1
+grpc_interceptor_duration_seconds_sum{interceptor="passthrough"} 0.04
+grpc_interceptor_duration_seconds_count{interceptor="passthrough"} 1
+`
+	if err := testutil.CollectAndCompare(GRPCInterceptorDuration, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDefaultUnaryServerInterceptors_WithInterceptorTiming(t *testing.T) {
+	GRPCInterceptorDuration.Reset()
+	t.Cleanup(GRPCInterceptorDuration.Reset)
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	for _, timing := range []bool{false, true} {
+		interceptors := DefaultUnaryServerInterceptors(WithInterceptorTiming(timing))
+		if len(interceptors) != 2 {
+			t.Fatalf("interceptors = %d, want 2", len(interceptors))
+		}
+
+		trace, metrics := interceptors[0], interceptors[1]
+		_, err := trace(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return metrics(ctx, req, info, handler)
+		})
+		if err != nil {
+			t.Fatalf("interceptor chain returned unexpected error: %v", err)
+		}
+
+		want := 0
+		if timing {
+			want = 2
+		}
+		if got := testutil.CollectAndCount(GRPCInterceptorDuration); got != want {
+			t.Errorf("timing=%v: interceptor duration series = %d, want %d", timing, got, want)
+		}
+	}
+}
+
+func TestDefaultUnaryServerInterceptors_MetricLabelOptions(t *testing.T) {
+	labelOpts := []Option{
+		WithCallerLabel("x-caller-service"),
+		WithOutcomeLabel(true),
+		WithConstLabels(map[string]string{"version": "v1.2.3"}),
+		WithBaggageMetricLabels("priority"),
+	}
+
+	assertPanics(t, "DefaultUnaryServerInterceptors without registry", func() {
+		DefaultUnaryServerInterceptors(labelOpts...)
+	})
+
+	reg := prometheus.NewRegistry()
+	interceptors := DefaultUnaryServerInterceptors(append(labelOpts, WithMetricsRegistry(reg))...)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-caller-service", "billing"))
+	if _, err := handler(ctx, nil); err != nil {
+		t.Fatalf("interceptor chain returned unexpected error: %v", err)
+	}
+
+	want := `
+# HELP grpc_requests_total Total number of gRPC requests
+# TYPE grpc_requests_total counter
+grpc_requests_total{caller="billing",code="OK",method="/test.Service/TestMethod",outcome="success",priority="unknown",version="v1.2.3"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "grpc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-anyway/framework-trace"
+
+	"go.opentelemetry.io/otel"
+)
+
+// flusher 支持强制导出缓冲 span 的 TracerProvider，例如 OTel SDK 的 TracerProvider
+type flusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// Shutdown 导出拦截器缓冲中尚未导出的 span，应在服务的关闭钩子中、停止 gRPC 服务之后调用
+// 先强制刷新全局 TracerProvider，再关闭 framework-trace 通过 trace.Init 创建的 TracerProvider；
+// 调用方自行设置的全局 TracerProvider 只刷新不关闭，其生命周期仍由调用方管理
+func Shutdown(ctx context.Context) error {
+	var errs []error
+	if tp, ok := otel.GetTracerProvider().(flusher); ok {
+		if err := tp.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := trace.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
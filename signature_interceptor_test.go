@@ -0,0 +1,170 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// testSignature 使用与 signRequest 相同的算法计算签名，用于构造测试请求
+func testSignature(t *testing.T, secret []byte, msg proto.Message) string {
+	t.Helper()
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSignatureVerifyUnaryInterceptor(t *testing.T) {
+	secret := []byte("shared-secret")
+	req, err := structpb.NewStruct(map[string]interface{}{"order_id": "42", "amount": 100.0, "currency": "USD"})
+	if err != nil {
+		t.Fatalf("NewStruct() error = %v", err)
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Signed",
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		req        interface{}
+		md         metadata.MD
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{
+			name:     "valid signature",
+			req:      req,
+			md:       metadata.Pairs("x-signature", testSignature(t, secret, req)),
+			wantCode: codes.OK,
+		},
+		{
+			name:       "signature from other secret",
+			req:        req,
+			md:         metadata.Pairs("x-signature", testSignature(t, []byte("other"), req)),
+			wantCode:   codes.Unauthenticated,
+			wantReason: "invalid_signature",
+		},
+		{
+			name:       "missing signature",
+			req:        req,
+			md:         metadata.MD{},
+			wantCode:   codes.Unauthenticated,
+			wantReason: "missing_signature",
+		},
+		{
+			name:       "malformed signature",
+			req:        req,
+			md:         metadata.Pairs("x-signature", "not-hex"),
+			wantCode:   codes.Unauthenticated,
+			wantReason: "missing_signature",
+		},
+		{
+			name:       "non proto request",
+			req:        "plain",
+			md:         metadata.Pairs("x-signature", testSignature(t, secret, req)),
+			wantCode:   codes.Unauthenticated,
+			wantReason: "invalid_signature",
+		},
+		{
+			name:     "exempt method",
+			opts:     []Option{WithSignatureExemptMethods(info.FullMethod)},
+			req:      req,
+			md:       metadata.MD{},
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := SignatureVerifyUnaryInterceptor(secret, "X-Signature", tt.opts...)
+
+			var before float64
+			if tt.wantReason != "" {
+				before = testutil.ToFloat64(GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, tt.wantReason))
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := interceptor(ctx, tt.req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "response", nil
+			})
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			if tt.wantReason != "" {
+				if got := testutil.ToFloat64(GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, tt.wantReason)) - before; got != 1 {
+					t.Errorf("rejected counter increment = %v, want 1", got)
+				}
+			}
+		})
+	}
+}
+
+func TestSignatureSignClientInterceptor(t *testing.T) {
+	secret := []byte("shared-secret")
+	req, err := structpb.NewStruct(map[string]interface{}{"b": 2.0, "a": 1.0, "c": "three"})
+	if err != nil {
+		t.Fatalf("NewStruct() error = %v", err)
+	}
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Signed",
+	}
+	verify := SignatureVerifyUnaryInterceptor(secret, "x-signature")
+
+	// 客户端签名后的 metadata 交给服务端校验拦截器，模拟一次完整的调用
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if got := md.Get("x-caller"); len(got) != 1 || got[0] != "billing" {
+			t.Errorf("x-caller = %v, want [billing]", got)
+		}
+		_, err := verify(metadata.NewIncomingContext(ctx, md), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "response", nil
+		})
+		return err
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-caller", "billing")
+	interceptor := SignatureSignClientInterceptor(secret, "x-signature")
+	for i := 0; i < 5; i++ {
+		if err := interceptor(ctx, info.FullMethod, req, nil, nil, invoker); err != nil {
+			t.Fatalf("signed call error = %v", err)
+		}
+	}
+
+	if err := SignatureSignClientInterceptor([]byte("other"), "x-signature")(ctx, info.FullMethod, req, nil, nil, invoker); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("call signed with other secret code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+	if err := interceptor(ctx, info.FullMethod, "plain", nil, nil, invoker); status.Code(err) != codes.Internal {
+		t.Errorf("non proto request code = %v, want %v", status.Code(err), codes.Internal)
+	}
+}
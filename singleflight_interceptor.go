@@ -0,0 +1,72 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// SingleflightKeyFunc 根据完整方法名和请求计算合并 key，返回空字符串表示该请求不参与合并
+type SingleflightKeyFunc func(fullMethod string, req interface{}) string
+
+// SingleflightUnaryInterceptor 创建请求合并拦截器，缓解读多的服务上大量相同请求同时到达造成的冲击
+// keyFn 返回相同非空 key 的并发请求只执行一次处理器，其余请求等待并共享同一个响应和错误，计入 GRPCCoalescedTotal。
+// keyFn 返回空字符串的请求（例如写操作）总是单独执行。
+// 处理器在不继承取消信号和超时的 context 上执行，首个请求被取消时其余请求仍能拿到结果；
+// 每个请求只等待到自身的 context 结束，之后返回对应的 Canceled 或 DeadlineExceeded。
+// 处理器 panic 时记录错误日志，所有等待的请求得到 Internal。
+// 注意：共享的响应是同一个对象，后续拦截器和处理器不应修改它
+func SingleflightUnaryInterceptor(keyFn SingleflightKeyFunc) grpc.UnaryServerInterceptor {
+	var g singleflight.Group
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := fullMethodFromInfo(info)
+		key := keyFn(method, req)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		// leader 表示本请求的处理器被执行，结果不是共享得到的
+		var leader bool
+		callCtx := context.WithoutCancel(ctx)
+		// 不同方法的 key 互不影响
+		ch := g.DoChan(method+"\x00"+key, func() (resp interface{}, err error) {
+			leader = true
+			// DoChan 在独立的 goroutine 中执行处理器，panic 无法被外层的 recovery 拦截器捕获，需要在这里转换为错误
+			defer func() {
+				if r := recover(); r != nil {
+					resp, err = nil, panicToError(callCtx, method, r)
+				}
+			}()
+			return handler(callCtx, req)
+		})
+
+		select {
+		case res := <-ch:
+			if !leader {
+				GRPCCoalescedTotal.WithLabelValues(method).Inc()
+			}
+			return res.Val, res.Err
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSingleflightUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Get",
+	}
+	counter := GRPCCoalescedTotal.WithLabelValues(info.FullMethod)
+	before := testutil.ToFloat64(counter)
+
+	const followers = 4
+	var keyed sync.WaitGroup
+	keyed.Add(followers + 1)
+	keyFn := func(fullMethod string, req interface{}) string {
+		keyed.Done()
+		return req.(string)
+	}
+
+	var calls int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		close(entered)
+		<-release
+		return "value", nil
+	}
+
+	interceptor := SingleflightUnaryInterceptor(keyFn)
+	results := make(chan interface{}, followers+1)
+	call := func() {
+		resp, err := interceptor(context.Background(), "same", info, handler)
+		if err != nil {
+			t.Errorf("interceptor() error = %v", err)
+		}
+		results <- resp
+	}
+
+	go call()
+	<-entered
+	for i := 0; i < followers; i++ {
+		go call()
+	}
+	// 等待所有请求计算完 key 并进入等待
+	keyed.Wait()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < followers+1; i++ {
+		if resp := <-results; resp != "value" {
+			t.Errorf("resp = %v, want value", resp)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("handler calls = %d, want 1", got)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != followers {
+		t.Errorf("coalesced counter increment = %v, want %d", got, followers)
+	}
+}
+
+func TestSingleflightUnaryInterceptor_EmptyKeyNotCoalesced(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Update",
+	}
+
+	var calls int
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, nil
+	}
+	interceptor := SingleflightUnaryInterceptor(func(string, interface{}) string { return "" })
+
+	for i := 0; i < 3; i++ {
+		if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+			t.Fatalf("interceptor() error = %v", err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+}
+
+func TestSingleflightUnaryInterceptor_WaiterCanceled(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Get",
+	}
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		close(entered)
+		<-release
+		return "value", nil
+	}
+	interceptor := SingleflightUnaryInterceptor(func(string, interface{}) string { return "same" })
+
+	leaderDone := make(chan interface{}, 1)
+	go func() {
+		resp, _ := interceptor(context.Background(), nil, info, handler)
+		leaderDone <- resp
+	}()
+	<-entered
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := interceptor(ctx, nil, info, handler)
+	if got := status.Code(err); got != codes.Canceled {
+		t.Errorf("waiter code = %v, want %v", got, codes.Canceled)
+	}
+
+	close(release)
+	if resp := <-leaderDone; resp != "value" {
+		t.Errorf("leader resp = %v, want value", resp)
+	}
+}
+
+func TestSingleflightUnaryInterceptor_LeaderCanceled(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Get",
+	}
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		close(entered)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		return "value", nil
+	}
+	interceptor := SingleflightUnaryInterceptor(func(string, interface{}) string { return "same" })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := interceptor(ctx, nil, info, handler)
+		leaderErr <- err
+	}()
+	<-entered
+
+	type result struct {
+		resp interface{}
+		err  error
+	}
+	follower := make(chan result, 1)
+	go func() {
+		resp, err := interceptor(context.Background(), nil, info, handler)
+		follower <- result{resp, err}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if got := status.Code(<-leaderErr); got != codes.Canceled {
+		t.Errorf("leader code = %v, want %v", got, codes.Canceled)
+	}
+	close(release)
+
+	if res := <-follower; res.err != nil || res.resp != "value" {
+		t.Errorf("follower = (%v, %v), want (value, nil)", res.resp, res.err)
+	}
+}
+
+func TestSingleflightUnaryInterceptor_HandlerPanic(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/Get",
+	}
+	interceptor := SingleflightUnaryInterceptor(func(string, interface{}) string { return "same" })
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if got := status.Code(err); got != codes.Internal {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.Internal)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxRequestSizeUnaryInterceptor 创建请求大小限制拦截器
+// 对于 proto.Message 类型的请求，序列化大小超过 maxBytes 时直接返回 InvalidArgument，
+// 非 proto 请求直接放行。与 gRPC 传输层的大小限制互补，提供语义化的错误和拒绝指标
+func MaxRequestSizeUnaryInterceptor(maxBytes int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if msg, ok := req.(proto.Message); ok {
+			if size := proto.Size(msg); size > maxBytes {
+				GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "request_too_large").Inc()
+				return nil, status.Errorf(codes.InvalidArgument, "request size %d bytes exceeds limit of %d bytes", size, maxBytes)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMaxRequestSizeUnaryInterceptor(t *testing.T) {
+	interceptor := MaxRequestSizeUnaryInterceptor(16)
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	tests := []struct {
+		name        string
+		req         interface{}
+		wantCode    codes.Code
+		wantHandler bool
+	}{
+		{
+			name:        "small proto request",
+			req:         wrapperspb.String("ok"),
+			wantCode:    codes.OK,
+			wantHandler: true,
+		},
+		{
+			name:        "oversized proto request",
+			req:         wrapperspb.String(strings.Repeat("x", 64)),
+			wantCode:    codes.InvalidArgument,
+			wantHandler: false,
+		},
+		{
+			name:        "non-proto request",
+			req:         strings.Repeat("x", 64),
+			wantCode:    codes.OK,
+			wantHandler: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerCalled bool
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCalled = true
+				return "response", nil
+			}
+
+			_, err := interceptor(context.Background(), tt.req, info, handler)
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			if handlerCalled != tt.wantHandler {
+				t.Errorf("handler called = %v, want %v", handlerCalled, tt.wantHandler)
+			}
+		})
+	}
+}
+
+func TestMaxRequestSizeUnaryInterceptor_NilInfo(t *testing.T) {
+	interceptor := MaxRequestSizeUnaryInterceptor(16)
+	_, err := interceptor(context.Background(), wrapperspb.String(strings.Repeat("x", 64)), nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxStreamMessagesInterceptor 创建流消息数量限制拦截器
+// 单个流上接收的消息超过 maxRecv 条时，RecvMsg 返回 ResourceExhausted，防止客户端无限发送消息耗尽内存。
+// maxRecv <= 0 时不限制
+func MaxStreamMessagesInterceptor(maxRecv int) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if maxRecv <= 0 {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &maxMessagesServerStream{
+			ServerStream: ss,
+			method:       fullMethodFromStreamInfo(info),
+			maxRecv:      maxRecv,
+		})
+	}
+}
+
+// maxMessagesServerStream 统计已接收消息数的 ServerStream
+type maxMessagesServerStream struct {
+	grpc.ServerStream
+	method   string
+	maxRecv  int
+	received int
+}
+
+func (s *maxMessagesServerStream) RecvMsg(m interface{}) error {
+	if s.received >= s.maxRecv {
+		if s.received == s.maxRecv {
+			// 只在首次超限时计数，处理器重复调用 RecvMsg 不会重复记录
+			s.received++
+			GRPCRequestRejectedTotal.WithLabelValues(s.method, "max_stream_messages").Inc()
+		}
+		return status.Errorf(codes.ResourceExhausted, "stream message limit %d exceeded", s.maxRecv)
+	}
+
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.received++
+	return nil
+}
+
+// MaxStreamSendInterceptor 创建流响应数量限制拦截器
+// 单个流上发送的消息超过 maxSend 条时，SendMsg 返回 ResourceExhausted，处理器返回该错误即中止流，
+// 防止无界的结果集压垮服务端和客户端。可通过 WithMethodSendLimits 为单个方法设置不同的上限，
+// 上限 <= 0 时不限制
+func MaxStreamSendInterceptor(maxSend int, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		method := fullMethodFromStreamInfo(info)
+		limit := maxSend
+		if methodLimit, ok := o.methodSendLimits[method]; ok {
+			limit = methodLimit
+		}
+		if limit <= 0 {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &maxSendServerStream{
+			ServerStream: ss,
+			method:       method,
+			maxSend:      limit,
+		})
+	}
+}
+
+// maxSendServerStream 统计已发送消息数的 ServerStream
+type maxSendServerStream struct {
+	grpc.ServerStream
+	method  string
+	maxSend int
+	sent    int
+}
+
+func (s *maxSendServerStream) SendMsg(m interface{}) error {
+	if s.sent >= s.maxSend {
+		if s.sent == s.maxSend {
+			// 只在首次超限时计数
+			s.sent++
+			GRPCRequestRejectedTotal.WithLabelValues(s.method, "max_stream_send").Inc()
+		}
+		return status.Errorf(codes.ResourceExhausted, "stream response limit %d exceeded", s.maxSend)
+	}
+
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.sent++
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMaxStreamMessagesInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{
+		FullMethod:     "/test.Service/Upload",
+		IsClientStream: true,
+	}
+	counter := GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, "max_stream_messages")
+	before := testutil.ToFloat64(counter)
+
+	var received int
+	var recvErr error
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		for i := 0; i < 5; i++ {
+			if recvErr = ss.RecvMsg(nil); recvErr != nil {
+				return recvErr
+			}
+			received++
+		}
+		return nil
+	}
+
+	interceptor := MaxStreamMessagesInterceptor(3)
+	err := interceptor(nil, newFakeServerStream(context.Background()), info, handler)
+
+	if received != 3 {
+		t.Errorf("received = %d, want 3", received)
+	}
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.ResourceExhausted)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("rejected counter increment = %v, want 1", got)
+	}
+}
+
+func TestMaxStreamMessagesInterceptor_NoLimit(t *testing.T) {
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		for i := 0; i < 100; i++ {
+			if err := ss.RecvMsg(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	interceptor := MaxStreamMessagesInterceptor(0)
+	if err := interceptor(nil, newFakeServerStream(context.Background()), &grpc.StreamServerInfo{}, handler); err != nil {
+		t.Errorf("interceptor() returned unexpected error: %v", err)
+	}
+}
+
+func TestMaxStreamMessagesInterceptor_PassesThroughEOF(t *testing.T) {
+	info := &grpc.StreamServerInfo{
+		FullMethod:     "/test.Service/ShortUpload",
+		IsClientStream: true,
+	}
+	counter := GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, "max_stream_messages")
+	before := testutil.ToFloat64(counter)
+
+	ss := newFakeServerStream(context.Background())
+	ss.recv = func(m interface{}) error {
+		if ss.received == 2 {
+			return io.EOF
+		}
+		return nil
+	}
+
+	var recvErr error
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		for recvErr == nil {
+			recvErr = ss.RecvMsg(nil)
+		}
+		return nil
+	}
+
+	if err := MaxStreamMessagesInterceptor(3)(nil, ss, info, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	if recvErr != io.EOF {
+		t.Errorf("RecvMsg() error = %v, want io.EOF", recvErr)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 0 {
+		t.Errorf("rejected counter increment = %v, want 0", got)
+	}
+}
+
+func TestMaxStreamSendInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{
+		FullMethod:     "/test.Service/List",
+		IsServerStream: true,
+	}
+	counter := GRPCRequestRejectedTotal.WithLabelValues(info.FullMethod, "max_stream_send")
+	before := testutil.ToFloat64(counter)
+
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		for i := 0; i < 10; i++ {
+			if err := ss.SendMsg(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ss := newFakeServerStream(context.Background())
+	err := MaxStreamSendInterceptor(3)(nil, ss, info, handler)
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Errorf("interceptor() code = %v, want %v", got, codes.ResourceExhausted)
+	}
+	if ss.sent != 3 {
+		t.Errorf("sent = %d, want 3", ss.sent)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("rejected counter increment = %v, want 1", got)
+	}
+}
+
+func TestMaxStreamSendInterceptor_MethodLimits(t *testing.T) {
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		for i := 0; i < 10; i++ {
+			if err := ss.SendMsg(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	interceptor := MaxStreamSendInterceptor(3, WithMethodSendLimits(map[string]int{
+		"/test.Service/Export": 0,
+		"/test.Service/Page":   5,
+	}))
+
+	tests := []struct {
+		method   string
+		wantSent int
+		wantCode codes.Code
+	}{
+		{"/test.Service/List", 3, codes.ResourceExhausted},
+		{"/test.Service/Page", 5, codes.ResourceExhausted},
+		{"/test.Service/Export", 10, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			ss := newFakeServerStream(context.Background())
+			err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: tt.method}, handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			if ss.sent != tt.wantSent {
+				t.Errorf("sent = %d, want %d", ss.sent, tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+// StreamMessageMetricsInterceptor 创建流消息指标拦截器
+// 按方法统计流上成功接收、发送的消息数（GRPCStreamMsgReceivedTotal、GRPCStreamMsgSentTotal），
+// 并记录相邻两次接收之间的间隔（GRPCStreamRecvInterval）。RecvMsg、SendMsg 的返回值原样透传
+func StreamMessageMetricsInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		method := fullMethodFromStreamInfo(info)
+		if o.skipMetrics(method) {
+			return handler(srv, ss)
+		}
+
+		label := o.methodLabel(method)
+		return handler(srv, &messageMetricsServerStream{
+			ServerStream: ss,
+			received:     GRPCStreamMsgReceivedTotal.WithLabelValues(label),
+			sent:         GRPCStreamMsgSentTotal.WithLabelValues(label),
+			recvInterval: GRPCStreamRecvInterval.WithLabelValues(label),
+		})
+	}
+}
+
+// messageMetricsServerStream 统计消息数和接收间隔的 ServerStream
+type messageMetricsServerStream struct {
+	grpc.ServerStream
+	received     prometheus.Counter
+	sent         prometheus.Counter
+	recvInterval prometheus.Observer
+	// lastRecv 上一次成功接收消息的时间，首条消息之前为零值
+	lastRecv time.Time
+}
+
+func (s *messageMetricsServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err != nil {
+		return err
+	}
+
+	now := nowFunc()
+	if !s.lastRecv.IsZero() {
+		s.recvInterval.Observe(now.Sub(s.lastRecv).Seconds())
+	}
+	s.lastRecv = now
+	s.received.Inc()
+	return nil
+}
+
+func (s *messageMetricsServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err != nil {
+		return err
+	}
+	s.sent.Inc()
+	return nil
+}
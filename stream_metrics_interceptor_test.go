@@ -0,0 +1,120 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+)
+
+func TestStreamMessageMetricsInterceptor(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0), step: 250 * time.Millisecond}
+	setNowFunc(t, clock.Now)
+
+	info := &grpc.StreamServerInfo{
+		FullMethod:     "/test.Service/Chat",
+		IsClientStream: true,
+		IsServerStream: true,
+	}
+	GRPCStreamRecvInterval.DeleteLabelValues(info.FullMethod)
+	t.Cleanup(func() { GRPCStreamRecvInterval.DeleteLabelValues(info.FullMethod) })
+	received := GRPCStreamMsgReceivedTotal.WithLabelValues(info.FullMethod)
+	sent := GRPCStreamMsgSentTotal.WithLabelValues(info.FullMethod)
+	receivedBefore := testutil.ToFloat64(received)
+	sentBefore := testutil.ToFloat64(sent)
+
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		for i := 0; i < 3; i++ {
+			if err := ss.RecvMsg(nil); err != nil {
+				return err
+			}
+		}
+		return ss.SendMsg(nil)
+	}
+
+	interceptor := StreamMessageMetricsInterceptor()
+	if err := interceptor(nil, newFakeServerStream(context.Background()), info, handler); err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+
+	if got := testutil.ToFloat64(received) - receivedBefore; got != 3 {
+		t.Errorf("received delta = %v, want 3", got)
+	}
+	if got := testutil.ToFloat64(sent) - sentBefore; got != 1 {
+		t.Errorf("sent delta = %v, want 1", got)
+	}
+
+	// 3 条消息产生 2 个 250ms 的间隔
+	want := `
+# HELP grpc_stream_recv_interval_seconds Time between consecutive messages received on gRPC server streams in seconds
+# TYPE grpc_stream_recv_interval_seconds histogram
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="0.005"} 0
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="0.01"} 0
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="0.025"} 0
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="0.05"} 0
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="0.1"} 0
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="0.25"} 2
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="0.5"} 2
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="1"} 2
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="2.5"} 2
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="5"} 2
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="10"} 2
+grpc_stream_recv_interval_seconds_bucket{method="/test.Service/Chat",le="+Inf"} 2
+grpc_stream_recv_interval_seconds_sum{method="/test.Service/Chat"} 0.5
+grpc_stream_recv_interval_seconds_count{method="/test.Service/Chat"} 2
+`
+	if err := testutil.CollectAndCompare(GRPCStreamRecvInterval, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamMessageMetricsInterceptor_PreservesErrors(t *testing.T) {
+	info := &grpc.StreamServerInfo{
+		FullMethod: "/test.Service/Broken",
+	}
+	received := GRPCStreamMsgReceivedTotal.WithLabelValues(info.FullMethod)
+	before := testutil.ToFloat64(received)
+
+	for _, want := range []error{io.EOF, errors.New("transport closed")} {
+		var recvErr, sendErr error
+		handler := func(srv interface{}, ss grpc.ServerStream) error {
+			recvErr = ss.RecvMsg(nil)
+			sendErr = ss.SendMsg(nil)
+			return nil
+		}
+
+		want := want
+		ss := newFakeServerStream(context.Background())
+		ss.recv = func(interface{}) error { return want }
+		ss.send = func(interface{}) error { return want }
+		if err := StreamMessageMetricsInterceptor()(nil, ss, info, handler); err != nil {
+			t.Fatalf("interceptor() error = %v", err)
+		}
+		if recvErr != want || sendErr != want {
+			t.Errorf("RecvMsg() = %v, SendMsg() = %v, want %v", recvErr, sendErr, want)
+		}
+	}
+
+	if got := testutil.ToFloat64(received) - before; got != 0 {
+		t.Errorf("received delta = %v, want 0", got)
+	}
+}
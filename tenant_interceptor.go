@@ -0,0 +1,55 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TenantUnaryInterceptor 创建租户解析拦截器
+// 从 header（例如 x-tenant-id）中读取租户 ID 放入 context，处理器可通过 TenantFromContext 获取，
+// 同时作为 tenant.id 属性添加到当前 span，因此应放在 TraceUnaryInterceptor 之后。
+// 缺少租户时默认放行，可通过 WithRequireTenant(true) 改为返回 InvalidArgument
+func TenantUnaryInterceptor(header string, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts...)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = o.enterChain(ctx)
+		md, _ := metadata.FromIncomingContext(ctx)
+		tenant := lookupMetadata(md, header)
+		if tenant == "" {
+			if o.requireTenant {
+				GRPCRequestRejectedTotal.WithLabelValues(fullMethodFromInfo(info), "missing_tenant").Inc()
+				return nil, status.Errorf(codes.InvalidArgument, "missing tenant metadata: %s", header)
+			}
+			return handler(ctx, req)
+		}
+
+		if span := oteltrace.SpanFromContext(ctx); span.IsRecording() {
+			span.SetAttributes(attribute.String("tenant.id", tenant))
+		}
+
+		return handler(contextWithTenant(ctx, tenant), req)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenantUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		md         metadata.MD
+		wantCode   codes.Code
+		wantTenant string
+	}{
+		{
+			name:       "tenant present",
+			md:         metadata.Pairs("x-tenant-id", "acme"),
+			wantCode:   codes.OK,
+			wantTenant: "acme",
+		},
+		{
+			name:     "missing tenant passes through by default",
+			md:       metadata.MD{},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "missing tenant rejected when required",
+			opts:     []Option{WithRequireTenant(true)},
+			md:       metadata.MD{},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := TenantUnaryInterceptor("X-Tenant-ID", tt.opts...)
+
+			var gotTenant string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				gotTenant = TenantFromContext(ctx)
+				return "response", nil
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := interceptor(ctx, nil, info, handler)
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			if gotTenant != tt.wantTenant {
+				t.Errorf("TenantFromContext() = %q, want %q", gotTenant, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestTenantUnaryInterceptor_SpanAttribute(t *testing.T) {
+	recorder := setupTestTracer(t)
+
+	trace := TraceUnaryInterceptor()
+	tenant := TenantUnaryInterceptor("x-tenant-id")
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "acme"))
+	_, err := trace(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return tenant(ctx, req, info, handler)
+	})
+	if err != nil {
+		t.Fatalf("interceptor() returned unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])[attribute.Key("tenant.id")]; got.AsString() != "acme" {
+		t.Errorf("tenant.id = %q, want %q", got.AsString(), "acme")
+	}
+}
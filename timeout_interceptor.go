@@ -0,0 +1,53 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errServerTimeout TimeoutUnaryInterceptor 强制超时时 context 的取消原因，用于与客户端 deadline 区分
+var errServerTimeout = errors.New("server-enforced timeout exceeded")
+
+// TimeoutUnaryInterceptor 创建服务端超时拦截器，为每个请求设置 timeout 的处理时限
+// 客户端 deadline 更短时以客户端为准。由本拦截器触发的超时返回 DeadlineExceeded 并计入 GRPCTimeoutTotal，
+// 客户端 deadline 到期不计入，便于调整服务端的超时配置。处理器成功返回时不视为超时，即使时限已到。
+// timeout <= 0 时不设置时限
+func TimeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeoutCause(ctx, timeout, errServerTimeout)
+		defer cancel()
+
+		// 处理器已经成功返回时保留结果，即使计时器在返回之后才触发
+		resp, err := handler(ctx, req)
+		if err != nil && errors.Is(context.Cause(ctx), errServerTimeout) {
+			GRPCTimeoutTotal.WithLabelValues(fullMethodFromInfo(info)).Inc()
+			return nil, status.Error(codes.DeadlineExceeded, errServerTimeout.Error())
+		}
+		return resp, err
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTimeoutUnaryInterceptor(t *testing.T) {
+	// 阻塞直到 context 结束的处理器
+	blocking := func(ctx context.Context, req interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	fast := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		timeout        time.Duration
+		clientDeadline time.Duration
+		handler        grpc.UnaryHandler
+		wantCode       codes.Code
+		wantInc        float64
+	}{
+		{
+			name:     "server timeout trips",
+			method:   "/test.Service/Slow",
+			timeout:  10 * time.Millisecond,
+			handler:  blocking,
+			wantCode: codes.DeadlineExceeded,
+			wantInc:  1,
+		},
+		{
+			name:           "shorter client deadline is not counted",
+			method:         "/test.Service/ClientDeadline",
+			timeout:        time.Minute,
+			clientDeadline: 10 * time.Millisecond,
+			handler:        blocking,
+			wantCode:       codes.DeadlineExceeded,
+			wantInc:        0,
+		},
+		{
+			name:    "handler succeeds after timer fires",
+			method:  "/test.Service/LateSuccess",
+			timeout: 10 * time.Millisecond,
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				<-ctx.Done()
+				return "response", nil
+			},
+			wantCode: codes.OK,
+			wantInc:  0,
+		},
+		{
+			name:     "fast handler",
+			method:   "/test.Service/Fast",
+			timeout:  time.Minute,
+			handler:  fast,
+			wantCode: codes.OK,
+			wantInc:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := TimeoutUnaryInterceptor(tt.timeout)
+			info := &grpc.UnaryServerInfo{
+				FullMethod: tt.method,
+			}
+
+			ctx := context.Background()
+			if tt.clientDeadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.clientDeadline)
+				defer cancel()
+			}
+
+			counter := GRPCTimeoutTotal.WithLabelValues(tt.method)
+			before := testutil.ToFloat64(counter)
+
+			_, err := interceptor(ctx, nil, info, tt.handler)
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor() code = %v, want %v", got, tt.wantCode)
+			}
+			if got := testutil.ToFloat64(counter) - before; got != tt.wantInc {
+				t.Errorf("timeout counter increment = %v, want %v", got, tt.wantInc)
+			}
+		})
+	}
+}
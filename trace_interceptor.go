@@ -18,9 +18,11 @@ package interceptor
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
-	"fmt"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-anyway/framework-log"
@@ -28,43 +30,164 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // TraceUnaryInterceptor 创建一个 gRPC 一元拦截器，支持 OpenTelemetry
-func TraceUnaryInterceptor() grpc.UnaryServerInterceptor {
+// 携带 x-debug metadata 的请求不受日志采样影响，完成日志至少以 Info 级别输出并附加 debug 和耗时字段，
+// span 上设置 debug=true；处理器可通过 DebugFromContext 判断是否输出额外的调试日志
+// 未配置 TracerProvider 且没有父 span 时不创建 span，关闭日志时不放入日志字段容器，仍然生成 requestID 并注入 context
+// 服务端安装 CompressionStatsHandler 时在 span 上记录请求使用的压缩算法
+func TraceUnaryInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	return traceUnaryInterceptor(newOptions(opts...), nil)
+}
+
+// TraceControl 在运行时调整 trace 拦截器行为的开关，可并发调用，拦截器在每个请求开始时读取
+type TraceControl struct {
+	payloadLogging atomic.Bool
+}
+
+// SetPayloadLogging 开启或关闭完成日志中的请求、响应内容，用于线上临时排查问题而无需重新部署
+func (c *TraceControl) SetPayloadLogging(enabled bool) {
+	c.payloadLogging.Store(enabled)
+}
+
+// PayloadLogging 返回当前是否记录请求、响应内容
+func (c *TraceControl) PayloadLogging() bool {
+	return c.payloadLogging.Load()
+}
+
+// TraceUnaryInterceptorWithControl 与 TraceUnaryInterceptor 相同，同时返回可在运行时调整拦截器行为的 TraceControl
+// 开关的初始值取自 opts，例如 WithPayloadLogging
+func TraceUnaryInterceptorWithControl(opts ...Option) (grpc.UnaryServerInterceptor, *TraceControl) {
+	o := newOptions(opts...)
+	ctl := &TraceControl{}
+	ctl.payloadLogging.Store(o.logPayloads)
+	return traceUnaryInterceptor(o, ctl), ctl
+}
+
+// traceUnaryInterceptor 创建 trace 拦截器，ctl 不为 nil 时运行时开关以 ctl 为准
+func traceUnaryInterceptor(o *options, ctl *TraceControl) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// 从 metadata 中提取追踪信息
+		if handler == nil {
+			return nil, errNilHandler
+		}
+
+		ctx = o.enterChain(ctx)
+		method := fullMethodFromInfo(info)
+		if o.skipTrace(method) {
+			return handler(ctx, req)
+		}
+		start := nowFunc()
+
+		// 运行时开关在请求开始时读取一次，保证同一请求内行为一致
+		logPayloads := o.logPayloads
+		if ctl != nil {
+			logPayloads = ctl.PayloadLogging()
+		}
+
+		// 从 metadata 中提取追踪信息，不传播追踪上下文的方法忽略上游传入的追踪 header
+		// 传播器没有任何字段（未配置传播器）时提取不会产生效果，直接跳过
+		noPropagation := o.noPropagation(method)
 		md, ok := metadata.FromIncomingContext(ctx)
-		if ok {
-			propagator := otel.GetTextMapPropagator()
+		if propagator := otel.GetTextMapPropagator(); ok && !noPropagation && len(propagator.Fields()) > 0 {
 			ctx = propagator.Extract(ctx, metadataCarrier(md))
 		}
 
-		// 开始新的 span
-		ctx, span := trace.StartSpan(ctx, info.FullMethod)
-		defer span.End()
+		// 要求必须携带追踪上下文时，W3C traceparent 或自定义 x-trace-id 均可满足
+		if o.requireTrace && !noPropagation && !oteltrace.SpanContextFromContext(ctx).IsValid() &&
+			(!ok || lookupMetadata(md, "x-trace-id") == "") {
+			GRPCRequestRejectedTotal.WithLabelValues(method, "missing_trace").Inc()
+			return nil, status.Error(codes.FailedPrecondition, "missing propagated trace context")
+		}
 
-		// 从 metadata 中提取 traceID 和 requestID
-		var traceID, requestID string
-		if ok && md != nil {
-			if values := md.Get("x-trace-id"); len(values) > 0 {
-				traceID = values[0]
+		// 开始新的 span，未配置追踪时 TracerProvider 返回不记录的 span，之后的属性和事件都按 recording 跳过
+		var startOpts []oteltrace.SpanStartOption
+		// 没有提取到有效的远程追踪上下文时显式创建根 span，不挂在 context 中已有的本地 span 下
+		if sc := oteltrace.SpanContextFromContext(ctx); noPropagation || o.forceNewRoot && !(sc.IsValid() && sc.IsRemote()) {
+			startOpts = append(startOpts, oteltrace.WithNewRoot())
+		}
+		// TracerProvider 已知为空操作且没有父 span 时不创建 span，使用 context 中不记录的空 span
+		tp := otel.GetTracerProvider()
+		hasParent := oteltrace.SpanContextFromContext(ctx).IsValid()
+		span := oteltrace.SpanFromContext(ctx)
+		if hasParent || !isNoopTracerProvider(tp) {
+			ctx, span = o.startSpan(ctx, o.spanName(method), startOpts...)
+			defer span.End()
+			if !hasParent && !span.SpanContext().IsValid() {
+				markNoopTracerProvider(tp)
+			}
+		}
+		recording := span.IsRecording()
+		if recording && len(o.spanAttributes) > 0 {
+			span.SetAttributes(o.spanAttributes...)
+		}
+
+		// 记录 span 开始时剩余的超时预算，便于排查 DeadlineExceeded
+		if deadline, hasDeadline := ctx.Deadline(); recording && hasDeadline {
+			span.SetAttributes(attribute.Int64("rpc.deadline_ms", time.Until(deadline).Milliseconds()))
+		}
+
+		// 记录请求使用的压缩算法，grpc-encoding 不会出现在 metadata 中，需要安装 CompressionStatsHandler
+		if recording {
+			if compression := requestCompressionFromContext(ctx); isCompressed(compression) {
+				span.SetAttributes(attribute.String("rpc.grpc.request_compression", compression))
 			}
-			if values := md.Get("x-request-id"); len(values) > 0 {
-				requestID = values[0]
+		}
+
+		// 记录客户端重试拦截器标记的尝试次数
+		attempt := attemptFromMetadata(md)
+		if recording {
+			span.SetAttributes(attribute.Int("rpc.grpc.attempt", attempt))
+		}
+
+		// 标记回放流量，便于在 trace 中区分
+		if recording && metadataFlag(md, replayHeader) {
+			span.SetAttributes(attribute.Bool("replay", true))
+		}
+
+		// 携带 x-debug 的请求绕过日志采样并输出更详细的日志，便于排查单个用户的问题
+		debug := metadataFlag(md, debugHeader)
+		if debug {
+			ctx = contextWithDebug(ctx)
+			if recording {
+				span.SetAttributes(attribute.Bool("debug", true))
 			}
 		}
 
+		// 从 metadata 中提取 traceID、requestID 和上游的 causationID
+		var traceID, requestID, causationID string
+		if ok && md != nil {
+			if !noPropagation {
+				traceID = lookupMetadata(md, "x-trace-id")
+			}
+			requestID = lookupMetadata(md, "x-request-id")
+			causationID = lookupMetadata(md, causationHeader)
+		}
+
 		// 如果不存在，从 OpenTelemetry context 获取
 		if traceID == "" {
 			traceID = trace.TraceIDFromContext(ctx)
 		}
-		if requestID == "" {
-			requestID = generateRequestID()
+		if !o.validRequestID(requestID) {
+			requestID = ""
+			if sc := oteltrace.SpanContextFromContext(ctx); o.requestIDFromSpan && sc.IsValid() {
+				requestID = sc.SpanID().String()
+			}
+			if requestID == "" {
+				requestID = generateRequestID(o.requestIDFormat)
+			}
 		}
 
 		// 注入到 context
@@ -74,121 +197,533 @@ func TraceUnaryInterceptor() grpc.UnaryServerInterceptor {
 		if requestID != "" {
 			ctx = log.ContextWithRequestID(ctx, requestID)
 		}
+		if causationID != "" && o.validRequestID(causationID) {
+			ctx = contextWithCausationID(ctx, causationID)
+		} else {
+			causationID = ""
+		}
+
+		// 按 traceID 决定是否记录正常日志，没有 traceID 时按 requestID 采样
+		sampleKey := traceID
+		if sampleKey == "" {
+			sampleKey = requestID
+		}
+		sampled := debug || o.logSampled(sampleKey)
 
 		// 记录请求开始
-		if traceID != "" || requestID != "" {
-			logger := log.FromContext(ctx)
-			logger.Info("gRPC request started",
-				zap.String("method", info.FullMethod),
+		if sampled && (traceID != "" || requestID != "") && logEnabled(zapcore.InfoLevel) {
+			fields := []zap.Field{
+				zap.String("method", method),
 				zap.String("trace_id", traceID),
 				zap.String("span_id", trace.SpanIDFromContext(ctx)),
-			)
+			}
+			if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+				fields = append(fields, zap.String("trace_flags", sc.TraceFlags().String()))
+			}
+			if causationID != "" {
+				fields = append(fields, zap.String("causation_id", causationID))
+			}
+			fields = append(fields, zap.Int("attempt", attempt))
+			if debug {
+				fields = append(fields, zap.Bool("debug", true))
+			}
+			log.FromContext(ctx).Info(o.startMessage, truncateFields(fields, o.maxFieldLength)...)
+		}
+
+		// 根据请求动态计算 span 属性，在调用处理器之前应用，保证处理器出错时属性依然存在
+		if recording && o.spanAttributeFunc != nil {
+			if attrs := o.spanAttributeFunc(ctx, req); len(attrs) > 0 {
+				span.SetAttributes(attrs...)
+			}
 		}
 
-		// 调用实际的处理器
+		// 从请求的 proto 字段中提取 span 属性
+		if recording && len(o.requestFieldAttrs) > 0 {
+			if attrs := requestFieldAttributes(req, o.requestFieldAttrs); len(attrs) > 0 {
+				span.SetAttributes(attrs...)
+			}
+		}
+
+		// 放入请求级别的日志字段容器，处理器追加的字段会出现在完成日志中，
+		// LoggerFromContext 基于它构建绑定了 method 字段的 logger；关闭日志时字段不会被输出，跳过
+		if logEnabled(zapcore.ErrorLevel) {
+			ctx = contextWithLogFields(ctx, method)
+			if causationID != "" {
+				AddLogFields(ctx, zap.String("causation_id", causationID))
+			}
+		}
+
+		// 调用实际的处理器，按需在 span 上标记处理器的起止时间
+		if recording && o.handlerBoundaryEvents {
+			span.AddEvent("handler.start")
+		}
 		resp, err := handler(ctx, req)
+		if recording && o.handlerBoundaryEvents {
+			span.AddEvent("handler.end")
+		}
+		err = o.normalizeError(err)
+
+		// 失败或慢请求的 span 按配置强制保留
+		if o.keepErrors || o.keepSlowerThan > 0 {
+			if reason := o.keepReason(err, since(start)); reason != "" {
+				o.keepSpan(ctx, span, method, reason, start, err)
+			}
+		}
 
 		// 设置 span 属性
-		span.SetAttributes(
-			attribute.String("rpc.method", info.FullMethod),
-			attribute.String("rpc.status_code", status.Code(err).String()),
-		)
-
-		// 记录请求完成
-		if traceID := log.TraceIDFromContext(ctx); traceID != "" || log.RequestIDFromContext(ctx) != "" {
-			logger := log.FromContext(ctx)
+		if recording {
+			span.SetAttributes(
+				attribute.String("rpc.method", method),
+				attribute.String("rpc.status_code", status.Code(err).String()),
+			)
+			o.setNumericStatus(span, err)
+		}
+
+		// 通过 trailer 返回 traceID 和 spanID，便于客户端关联服务端日志
+		if o.traceTrailers {
+			setTraceTrailer(ctx, log.TraceIDFromContext(ctx), trace.SpanIDFromContext(ctx))
+		}
+
+		// 记录请求完成，失败请求不受采样影响
+		level := o.completionLevel(err)
+		if debug && level < zapcore.InfoLevel {
+			level = zapcore.InfoLevel
+		}
+		if traceID := log.TraceIDFromContext(ctx); (sampled || err != nil) &&
+			(traceID != "" || log.RequestIDFromContext(ctx) != "") && logEnabled(level) {
+			logger := loggerFromContext(ctx, o.maxFieldLength)
+			fields := []zap.Field{
+				zap.String("method", method),
+			}
+			if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+				fields = append(fields, zap.String("trace_flags", sc.TraceFlags().String()))
+			}
+			if debug {
+				fields = append(fields, zap.Bool("debug", true), zap.Duration("duration", since(start)))
+			}
+			if logPayloads {
+				fields = appendPayloadFields(fields, req, resp, err, o.payloadEncoder)
+			}
 			if err != nil {
-				logger.Error("gRPC request failed",
-					zap.String("method", info.FullMethod),
-					zap.Error(err),
-				)
+				fields = append(fields, zap.Error(err))
+				if details := statusDetailsJSON(err); details != "" {
+					fields = append(fields, zap.String("error_details", details))
+				}
+				if cause := cancelCause(ctx, err); cause != nil {
+					fields = append(fields, zap.NamedError("cancel_cause", cause))
+				}
+				logger.Log(level, o.failMessage, truncateFields(fields, o.maxFieldLength)...)
+				if o.syncOnError {
+					_ = syncLogger(logger)
+				}
 			} else {
-				logger.Info("gRPC request completed",
-					zap.String("method", info.FullMethod),
-				)
+				logger.Log(level, o.completeMessage, truncateFields(fields, o.maxFieldLength)...)
 			}
 		}
 
+		// 在错误详情中附加 traceID，客户端可通过 TraceIDFromError 提取
+		if o.traceIDInErrors {
+			err = withTraceIDDetail(err, log.TraceIDFromContext(ctx), log.RequestIDFromContext(ctx))
+		}
+
+		// 每个请求一条固定字段的访问日志，不受日志采样影响
+		if o.accessLog && logEnabled(zapcore.InfoLevel) {
+			logAccess(ctx, method, err, since(start))
+		}
+
 		return resp, err
 	}
 }
 
+// logAccess 记录固定字段的访问日志：method、code、duration_ms、trace_id、request_id、peer
+func logAccess(ctx context.Context, method string, err error, elapsed time.Duration) {
+	peerAddr := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddr = p.Addr.String()
+	}
+
+	log.GetLogger().Info(accessLogMessage,
+		zap.String("method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Float64("duration_ms", float64(elapsed)/float64(time.Millisecond)),
+		zap.String("trace_id", log.TraceIDFromContext(ctx)),
+		zap.String("request_id", log.RequestIDFromContext(ctx)),
+		zap.String("peer", peerAddr),
+	)
+}
+
+// syncLogger 刷新 logger 的缓冲，测试中可以替换以观察调用
+var syncLogger = func(logger *zap.Logger) error {
+	return logger.Sync()
+}
+
+// logEnabled 判断全局 logger 是否启用了指定级别，日志会被丢弃时避免构造 logger 和字段
+func logEnabled(level zapcore.Level) bool {
+	return log.GetLogger().Core().Enabled(level)
+}
+
+// noopTracerProvider 最近一次观察到的空操作 TracerProvider，存放 tracerProviderRef
+var noopTracerProvider atomic.Value
+
+// tracerProviderRef 包装 TracerProvider，使 atomic.Value 可以存放不同具体类型的 provider
+type tracerProviderRef struct {
+	tp oteltrace.TracerProvider
+}
+
+// markNoopTracerProvider 记录 tp 为空操作：没有父 span 时 tp 创建的 span 没有有效的 span context。
+// SDK 的 TracerProvider 即使不采样也会生成有效的 traceID，因此只有未配置追踪时才会被标记；
+// otel.SetTracerProvider 会替换全局 provider，标记随之失效
+func markNoopTracerProvider(tp oteltrace.TracerProvider) {
+	if reflect.TypeOf(tp).Comparable() {
+		noopTracerProvider.Store(tracerProviderRef{tp: tp})
+	}
+}
+
+// isNoopTracerProvider 判断 tp 是否已被标记为空操作
+func isNoopTracerProvider(tp oteltrace.TracerProvider) bool {
+	ref, _ := noopTracerProvider.Load().(tracerProviderRef)
+	return ref.tp != nil && reflect.TypeOf(ref.tp) == reflect.TypeOf(tp) && ref.tp == tp
+}
+
+// completionLevel 返回请求完成日志的级别
+func completionLevel(err error) zapcore.Level {
+	if err != nil {
+		return zapcore.ErrorLevel
+	}
+	return zapcore.InfoLevel
+}
+
+// cancelCause 在 err 表示取消或超时时返回请求 context 的取消原因（context.Cause），
+// 原因与 ctx.Err() 相同（未通过 WithCancelCause 等设置原因）时返回 nil，避免重复记录
+func cancelCause(ctx context.Context, err error) error {
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded:
+	default:
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			return nil
+		}
+	}
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		return nil
+	}
+	if cause := context.Cause(ctx); cause != nil && cause != ctxErr {
+		return cause
+	}
+	return nil
+}
+
+// statusDetailsJSON 将 gRPC status 中的 details 编码为 JSON 数组，没有 details 时返回空字符串
+// 无法编码的 detail 会被跳过，不影响其他 detail 的输出
+func statusDetailsJSON(err error) string {
+	details := status.Convert(err).Details()
+	if len(details) == 0 {
+		return ""
+	}
+
+	encoded := make([]json.RawMessage, 0, len(details))
+	for _, detail := range details {
+		msg, ok := detail.(proto.Message)
+		if !ok {
+			continue
+		}
+		b, err := protojson.Marshal(msg)
+		if err != nil {
+			continue
+		}
+		encoded = append(encoded, b)
+	}
+	if len(encoded) == 0 {
+		return ""
+	}
+
+	b, err := json.Marshal(encoded)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// setTraceTrailer 将 traceID 和 spanID 写入响应 trailer
+// 上下文中没有 ServerTransportStream（例如单元测试直接调用）时忽略错误
+func setTraceTrailer(ctx context.Context, traceID, spanID string) {
+	md := metadata.MD{}
+	if traceID != "" {
+		md.Set("x-trace-id", traceID)
+	}
+	if spanID != "" {
+		md.Set("x-span-id", spanID)
+	}
+	if len(md) == 0 {
+		return
+	}
+	_ = grpc.SetTrailer(ctx, md)
+}
+
+// traceErrorReason、traceErrorDomain 附加到错误中的 ErrorInfo 的 reason 和 domain，用于与业务自身的 ErrorInfo 区分
+const (
+	traceErrorReason = "TRACE_ID"
+	traceErrorDomain = "github.com/go-anyway/framework-interceptor"
+)
+
+// withTraceIDDetail 在 err 对应的 status 的 details 末尾追加携带 trace_id、request_id 的 ErrorInfo，保留已有的 details
+// err 为 nil、traceID 为空或追加失败时原样返回 err
+func withTraceIDDetail(err error, traceID, requestID string) error {
+	if err == nil || traceID == "" {
+		return err
+	}
+	fields := map[string]string{"trace_id": traceID}
+	if requestID != "" {
+		fields["request_id"] = requestID
+	}
+	st, detailErr := status.Convert(err).WithDetails(&errdetails.ErrorInfo{
+		Reason:   traceErrorReason,
+		Domain:   traceErrorDomain,
+		Metadata: fields,
+	})
+	if detailErr != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// TraceIDFromError 从服务端开启 WithTraceIDInErrors 后返回的错误中提取 traceID，没有时返回空字符串
+// 客户端可在报告错误时附带该 traceID，便于关联服务端日志
+func TraceIDFromError(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == traceErrorReason && info.GetDomain() == traceErrorDomain {
+			return info.GetMetadata()["trace_id"]
+		}
+	}
+	return ""
+}
+
 // metadataCarrier 实现 TextMapCarrier 接口（用于 OpenTelemetry 传播）
 type metadataCarrier metadata.MD
 
-func (m metadataCarrier) Get(key string) string {
-	values := metadata.MD(m).Get(key)
-	if len(values) > 0 {
-		return values[0]
+// causationHeader 标记引起本次请求的上游请求 ID 的 metadata key
+const causationHeader = "x-causation-id"
+
+// setCausationHeader 将当前请求的 requestID 作为下游调用的 causationID 写入 md，md 中已显式设置时不覆盖
+func setCausationHeader(ctx context.Context, md metadata.MD) {
+	requestID := log.RequestIDFromContext(ctx)
+	if requestID == "" || len(md.Get(causationHeader)) > 0 {
+		return
 	}
-	return ""
+	md.Set(causationHeader, requestID)
 }
 
+// injectTraceContext 使用全局传播器将 ctx 中的追踪上下文注入到 md，客户端拦截器和 InjectedTraceContext 共用
+func injectTraceContext(ctx context.Context, md metadata.MD) {
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+}
+
+// InjectedTraceContext 返回客户端拦截器基于 ctx 会注入到下游的追踪上下文，不发起实际调用
+// 与拦截器使用相同的传播器逻辑：先注入到空的 metadata，再从中提取。没有可传播的追踪上下文时返回空字符串和 false。
+// 客户端拦截器会先创建子 span 再注入，因此在 invoker 中调用即可得到实际注入的值，便于在集成测试中断言
+func InjectedTraceContext(ctx context.Context) (traceID, spanID string, sampled bool) {
+	md := metadata.MD{}
+	injectTraceContext(ctx, md)
+
+	sc := oteltrace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(), metadataCarrier(md)))
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), sc.IsSampled()
+}
+
+// NewMetadataCarrier 返回基于 gRPC metadata 的 TextMapCarrier，可在自定义拦截器中配合 OpenTelemetry 传播器注入、提取追踪上下文
+// 与本包拦截器的语义一致：读取时兼容大小写和代理前缀，写入直接修改 md
+func NewMetadataCarrier(md metadata.MD) propagation.TextMapCarrier {
+	return metadataCarrier(md)
+}
+
+func (m metadataCarrier) Get(key string) string {
+	return lookupMetadata(metadata.MD(m), key)
+}
+
+// Set 写入 key 的唯一值，同时删除大小写不同的同名 key，
+// 保证多次注入或组合传播器重复注入时不会产生重复的 traceparent 等 header
 func (m metadataCarrier) Set(key, value string) {
-	metadata.MD(m).Set(key, value)
+	lower := strings.ToLower(key)
+	for k := range m {
+		if k != lower && strings.ToLower(k) == lower {
+			delete(m, k)
+		}
+	}
+	metadata.MD(m).Set(lower, value)
 }
 
+// Keys 返回可供传播器使用的 key，跳过伪 header（以 ":" 开头）和二进制 header（以 "-bin" 结尾），
+// 这些 header 的值不是文本，传播器无法解析
 func (m metadataCarrier) Keys() []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
+		if strings.HasPrefix(k, ":") || strings.HasSuffix(k, "-bin") {
+			continue
+		}
 		keys = append(keys, k)
 	}
 	return keys
 }
 
+// metadataKeyPrefixes 代理（如 gRPC-Web、grpc-gateway）透传 header 时可能添加的前缀
+var metadataKeyPrefixes = []string{"grpcweb-", "grpcgateway-"}
+
+// debugHeader 开启单个请求详细日志的 metadata key
+const debugHeader = "x-debug"
+
+// replayHeader 标记回放或测试流量的 metadata key
+const replayHeader = "x-replay"
+
+// metadataFlag 判断 metadata 中 key 对应的开关是否开启，空值、"0" 和 "false" 视为未开启
+func metadataFlag(md metadata.MD, key string) bool {
+	switch strings.ToLower(strings.TrimSpace(lookupMetadata(md, key))) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// isReplay 判断请求是否通过 x-replay 标记为回放流量
+func isReplay(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return metadataFlag(md, replayHeader)
+}
+
+// lookupMetadata 从 metadata 中查找 key 对应的第一个值
+// gRPC 收到的 metadata key 均为小写，key 统一转为小写后直接查找，同时兼容带有 metadataKeyPrefixes 前缀的 header，
+// 未加前缀的 key 优先
+func lookupMetadata(md metadata.MD, key string) string {
+	key = strings.ToLower(key)
+	if values := md[key]; len(values) > 0 {
+		return values[0]
+	}
+
+	// 在栈上拼接带前缀的 key，map 查找时的 string 转换不会分配内存
+	var buf [64]byte
+	for _, prefix := range metadataKeyPrefixes {
+		prefixed := append(append(buf[:0], prefix...), key...)
+		if values := md[string(prefixed)]; len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
 // TraceUnaryClientInterceptor 创建一个 gRPC 客户端一元拦截器，支持 OpenTelemetry
-// 用于在客户端调用 gRPC 服务时注入追踪上下文并创建子 span
-func TraceUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+// 用于在客户端调用 gRPC 服务时注入追踪上下文并创建子 span，通过 LinkSpans 放入 context 的链接会附加到该 span
+func TraceUnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts...)
+
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = o.enterChain(ctx)
+		if o.propagationWarnings {
+			warnMissingParentSpan(ctx, method)
+		}
+
 		// 开始新的 span（作为子 span）
-		ctx, span := trace.StartSpan(ctx, method)
+		ctx, span := o.startSpan(ctx, o.spanName(method), oteltrace.WithLinks(spanLinksFromContext(ctx)...))
 		defer span.End()
+		// 未配置追踪时 span 为空操作，跳过属性构造，但仍然注入传播 header 和记录日志
+		recording := span.IsRecording()
+		if recording && len(o.spanAttributes) > 0 {
+			span.SetAttributes(o.spanAttributes...)
+		}
 
 		// 从 context 中提取追踪信息并注入到 metadata
-		propagator := otel.GetTextMapPropagator()
 		md, ok := metadata.FromOutgoingContext(ctx)
 		if !ok {
 			md = metadata.MD{}
 		}
 
 		// 使用 OpenTelemetry 标准传播机制注入追踪上下文
-		carrier := metadataCarrier(md)
-		propagator.Inject(ctx, carrier)
+		injectTraceContext(ctx, md)
+		setCausationHeader(ctx, md)
+
+		// 记录调用使用的压缩算法，调用选项优先于 metadata
+		if recording {
+			if compression := clientCompression(md, opts); isCompressed(compression) {
+				span.SetAttributes(attribute.String("rpc.grpc.request_compression", compression))
+			}
+		}
 
 		// 将 metadata 添加到 context
 		ctx = metadata.NewOutgoingContext(ctx, md)
 
 		// 设置 span 属性
-		span.SetAttributes(
-			attribute.String("rpc.method", method),
-			attribute.String("rpc.system", "grpc"),
-		)
+		if recording {
+			span.SetAttributes(
+				attribute.String("rpc.method", method),
+				attribute.String("rpc.system", "grpc"),
+			)
+		}
 
 		// 调用实际的 gRPC 方法
 		err := invoker(ctx, method, req, reply, cc, opts...)
 
 		// 设置状态码
-		if err != nil {
-			span.SetAttributes(
-				attribute.String("rpc.status_code", status.Code(err).String()),
-			)
-			span.RecordError(err)
-		} else {
-			span.SetAttributes(
-				attribute.String("rpc.status_code", "OK"),
-			)
+		if recording {
+			if err != nil {
+				span.SetAttributes(
+					attribute.String("rpc.status_code", status.Code(err).String()),
+				)
+				span.RecordError(err)
+			} else {
+				span.SetAttributes(
+					attribute.String("rpc.status_code", "OK"),
+				)
+			}
+			o.setNumericStatus(span, err)
+		}
+
+		// 记录客户端调用日志，包含注入到下游的 traceID
+		if o.clientLogging && logEnabled(completionLevel(err)) {
+			logClientCall(ctx, method, err, o.maxFieldLength)
 		}
 
 		return err
 	}
 }
 
-// generateRequestID 生成请求ID
-func generateRequestID() string {
-	b := make([]byte, 16)
-	if _, err := rand.Read(b); err != nil {
-		// 如果随机数生成失败，使用时间戳作为后备方案
-		return fmt.Sprintf("%x", time.Now().UnixNano())
+// warnMissingParentSpan 出站调用的 context 中没有有效的 span context 时记录警告，
+// 通常说明处理器使用了 context.Background() 而不是请求的 ctx 发起下游调用，导致追踪链路断开
+func warnMissingParentSpan(ctx context.Context, method string) {
+	if oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	log.FromContext(ctx).Warn("Outgoing gRPC call has no parent span, trace propagation may be broken",
+		zap.String("method", method),
+	)
+}
+
+// logClientCall 记录客户端调用结束日志，字符串字段截断到 maxFieldLength 个字符
+func logClientCall(ctx context.Context, method string, err error, maxFieldLength int) {
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("trace_id", trace.TraceIDFromContext(ctx)),
+		zap.String("status_code", status.Code(err).String()),
+	}
+
+	logger := log.FromContext(ctx)
+	if err != nil {
+		logger.Error("gRPC client call failed", truncateFields(append(fields, zap.Error(err)), maxFieldLength)...)
+		return
+	}
+	logger.Info("gRPC client call completed", truncateFields(fields, maxFieldLength)...)
+}
+
+// clientCompression 返回客户端调用使用的压缩算法名称，优先读取 grpc.UseCompressor 调用选项，其次读取 grpc-encoding metadata
+func clientCompression(md metadata.MD, opts []grpc.CallOption) string {
+	for i := len(opts) - 1; i >= 0; i-- {
+		if c, ok := opts[i].(grpc.CompressorCallOption); ok {
+			return c.CompressorType
+		}
 	}
-	return hex.EncodeToString(b)
+	return lookupMetadata(md, "grpc-encoding")
+}
+
+// isCompressed 判断压缩算法名称是否表示启用了压缩
+func isCompressed(name string) bool {
+	return name != "" && name != "identity"
 }
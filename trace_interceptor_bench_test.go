@@ -0,0 +1,146 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func BenchmarkTraceUnaryInterceptor(b *testing.B) {
+	// 关闭日志且不配置追踪，走快速路径
+	log.Init(log.WithLevel("fatal"))
+	defer log.Init()
+
+	interceptor := TraceUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "bench-request",
+		"x-trace-id", "bench-trace",
+		"content-type", "application/grpc",
+		"user-agent", "grpc-go",
+	))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = interceptor(ctx, nil, info, handler)
+	}
+}
+
+// traceFastPathMaxAllocs 未配置追踪且关闭日志时 trace 拦截器每个请求允许的最大分配次数：
+// 复制 incoming metadata，以及注入 traceID、requestID 的 context
+const traceFastPathMaxAllocs = 10
+
+func TestTraceUnaryInterceptor_FastPathAllocs(t *testing.T) {
+	log.Init(log.WithLevel("fatal"))
+	defer log.Init()
+
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	interceptor := TraceUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "bench-request",
+		"x-trace-id", "bench-trace",
+		"content-type", "application/grpc",
+		"user-agent", "grpc-go",
+	))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = interceptor(ctx, nil, info, handler)
+	})
+	if allocs > traceFastPathMaxAllocs {
+		t.Errorf("allocs per request = %v, want <= %d", allocs, traceFastPathMaxAllocs)
+	}
+}
+
+// BenchmarkTraceUnaryInterceptor_TracerModes 对比未配置追踪、只配置传播器和配置了 TracerProvider 时的开销
+func BenchmarkTraceUnaryInterceptor_TracerModes(b *testing.B) {
+	log.Init(log.WithLevel("fatal"))
+	defer log.Init()
+
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "bench-request",
+		"content-type", "application/grpc",
+		"user-agent", "grpc-go",
+	))
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/TestMethod",
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	modes := []struct {
+		name       string
+		provider   oteltrace.TracerProvider
+		propagator propagation.TextMapPropagator
+	}{
+		{"noop", noop.NewTracerProvider(), propagation.NewCompositeTextMapPropagator()},
+		{"noop_with_propagator", noop.NewTracerProvider(), propagation.TraceContext{}},
+		{"configured", tp, propagation.TraceContext{}},
+	}
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			otel.SetTracerProvider(mode.provider)
+			otel.SetTextMapPropagator(mode.propagator)
+			interceptor := TraceUnaryInterceptor()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = interceptor(ctx, nil, info, handler)
+			}
+		})
+	}
+}